@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"financing-aggregator/internal/banksim"
+	"financing-aggregator/internal/config"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	cfg := config.ReadConfig()
+
+	var logger *zap.Logger
+	if cfg.Env == "prod" {
+		logger, _ = zap.NewProduction()
+	} else {
+		logger, _ = zap.NewDevelopment()
+	}
+	defer logger.Sync()
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.BankSim.Port),
+		Handler: banksim.New(cfg.BankSim, logger).Handler(),
+	}
+
+	go func() {
+		logger.Info("bank simulator started", zap.Int("port", cfg.BankSim.Port))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("failed to start bank simulator", zap.Error(err))
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Fatal("forcefully shutting down bank simulator", zap.Error(err))
+	}
+
+	logger.Info("bank simulator stopped")
+}
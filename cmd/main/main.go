@@ -20,6 +20,9 @@ import (
 // @securityDefinitions.apikey 	BearerAuth
 // @in 							header
 // @name 						Authorization
+// @securityDefinitions.apikey 	APIKeyAuth
+// @in 							header
+// @name 						X-API-Key
 func main() {
 	cfg := config.ReadConfig()
 
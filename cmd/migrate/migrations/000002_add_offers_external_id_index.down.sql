@@ -0,0 +1 @@
+DROP INDEX IF EXISTS offers_bank_external_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS offers_bank_external_id_idx ON offers (bank, external_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS submissions;
+DROP TYPE IF EXISTS submission_status_enum;
@@ -0,0 +1,17 @@
+CREATE TYPE submission_status_enum AS ENUM ('PENDING', 'SUBMITTED', 'FAILED');
+
+CREATE TABLE IF NOT EXISTS submissions
+(
+    id              UUID PRIMARY KEY,
+    created_at      TIMESTAMPTZ            NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ            NOT NULL DEFAULT NOW(),
+    deleted_at      TIMESTAMPTZ,
+    application_id  UUID                   NOT NULL REFERENCES applications (id),
+    bank            VARCHAR(64)            NOT NULL,
+    status          submission_status_enum NOT NULL DEFAULT 'PENDING',
+    attempts        INT                    NOT NULL DEFAULT 0,
+    last_error      TEXT                   NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ            NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS submissions_pending_idx ON submissions (next_attempt_at) WHERE status = 'PENDING';
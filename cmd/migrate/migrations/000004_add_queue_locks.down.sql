@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS offers_draft_idx;
+
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS locked_by,
+    DROP COLUMN IF EXISTS locked_until;
+
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS locked_by,
+    DROP COLUMN IF EXISTS locked_until;
@@ -0,0 +1,9 @@
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS locked_by    VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+ALTER TABLE offers
+    ADD COLUMN IF NOT EXISTS locked_by    VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS offers_draft_idx ON offers (created_at) WHERE status = 'DRAFT';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS offers_draft_next_poll_at_idx;
+CREATE INDEX IF NOT EXISTS offers_draft_idx ON offers (created_at) WHERE status = 'DRAFT';
+
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS next_poll_at,
+    DROP COLUMN IF EXISTS poll_attempts;
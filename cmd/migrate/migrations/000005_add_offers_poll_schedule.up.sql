@@ -0,0 +1,6 @@
+ALTER TABLE offers
+    ADD COLUMN IF NOT EXISTS next_poll_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS poll_attempts INT         NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS offers_draft_idx;
+CREATE INDEX IF NOT EXISTS offers_draft_next_poll_at_idx ON offers (next_poll_at) WHERE status = 'DRAFT';
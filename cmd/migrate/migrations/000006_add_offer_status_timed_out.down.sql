@@ -0,0 +1,15 @@
+UPDATE offers SET status = 'DECLINED' WHERE status = 'TIMED_OUT';
+
+DROP INDEX IF EXISTS offers_draft_next_poll_at_idx;
+
+ALTER TYPE offer_status_enum RENAME TO offer_status_enum_old;
+CREATE TYPE offer_status_enum AS ENUM ('DRAFT', 'PROCESSED', 'DECLINED');
+
+ALTER TABLE offers
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE offer_status_enum USING status::TEXT::offer_status_enum,
+    ALTER COLUMN status SET DEFAULT 'DRAFT';
+
+DROP TYPE offer_status_enum_old;
+
+CREATE INDEX IF NOT EXISTS offers_draft_next_poll_at_idx ON offers (next_poll_at) WHERE status = 'DRAFT';
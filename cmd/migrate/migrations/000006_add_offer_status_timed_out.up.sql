@@ -0,0 +1 @@
+ALTER TYPE offer_status_enum ADD VALUE IF NOT EXISTS 'TIMED_OUT';
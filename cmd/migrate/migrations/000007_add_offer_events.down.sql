@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS offer_events;
+DROP TYPE IF EXISTS offer_event_type_enum;
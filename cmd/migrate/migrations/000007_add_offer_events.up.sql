@@ -0,0 +1,15 @@
+CREATE TYPE offer_event_type_enum AS ENUM ('SUBMITTED', 'SUBMISSION_FAILED', 'BANK_RESPONSE', 'STATUS_CHANGED', 'TERMS_CHANGED', 'BROADCAST');
+
+CREATE TABLE IF NOT EXISTS offer_events
+(
+    id             UUID PRIMARY KEY,
+    created_at     TIMESTAMPTZ           NOT NULL DEFAULT NOW(),
+    application_id UUID                  NOT NULL REFERENCES applications (id),
+    offer_id       UUID REFERENCES offers (id),
+    bank           VARCHAR(64)           NOT NULL DEFAULT '',
+    type           offer_event_type_enum NOT NULL,
+    status         VARCHAR(32)           NOT NULL DEFAULT '',
+    details        TEXT                  NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS offer_events_application_id_idx ON offer_events (application_id, created_at);
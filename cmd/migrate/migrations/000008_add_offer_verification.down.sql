@@ -0,0 +1,12 @@
+DELETE FROM offer_events WHERE type = 'VERIFICATION_FAILED';
+
+ALTER TYPE offer_event_type_enum RENAME TO offer_event_type_enum_old;
+CREATE TYPE offer_event_type_enum AS ENUM ('SUBMITTED', 'SUBMISSION_FAILED', 'BANK_RESPONSE', 'STATUS_CHANGED', 'TERMS_CHANGED', 'BROADCAST');
+ALTER TABLE offer_events ALTER COLUMN type TYPE offer_event_type_enum USING type::TEXT::offer_event_type_enum;
+DROP TYPE offer_event_type_enum_old;
+
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS computed_annual_percentage_rate,
+    DROP COLUMN IF EXISTS computed_total_repayment_amount,
+    DROP COLUMN IF EXISTS verification,
+    DROP COLUMN IF EXISTS quarantined;
@@ -0,0 +1,7 @@
+ALTER TABLE offers
+    ADD COLUMN IF NOT EXISTS computed_annual_percentage_rate NUMERIC(10, 3) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS computed_total_repayment_amount NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS verification                    VARCHAR(16)    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS quarantined                     BOOLEAN        NOT NULL DEFAULT FALSE;
+
+ALTER TYPE offer_event_type_enum ADD VALUE IF NOT EXISTS 'VERIFICATION_FAILED';
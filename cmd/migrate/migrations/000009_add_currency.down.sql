@@ -0,0 +1,5 @@
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE applications
+    DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    key           VARCHAR(255) PRIMARY KEY,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    request_hash  VARCHAR(64) NOT NULL,
+    status_code   INT         NOT NULL DEFAULT 0,
+    response_body BYTEA
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS applications_phone_created_at_idx;
+DROP INDEX IF EXISTS applications_email_created_at_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS applications_email_created_at_idx ON applications (email, created_at);
+CREATE INDEX IF NOT EXISTS applications_phone_created_at_idx ON applications (phone, created_at);
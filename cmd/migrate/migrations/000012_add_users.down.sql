@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS applications_user_id_idx;
+
+ALTER TABLE applications
+    DROP COLUMN IF EXISTS user_id;
+
+DROP TABLE IF EXISTS users;
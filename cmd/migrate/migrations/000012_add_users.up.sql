@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id                         UUID PRIMARY KEY,
+    created_at                 TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at                 TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    deleted_at                 TIMESTAMPTZ,
+    email                      VARCHAR(255)   NOT NULL,
+    password_hash              VARCHAR(72)    NOT NULL,
+    phone                      VARCHAR(32)    NOT NULL DEFAULT '',
+    monthly_income             NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    monthly_expenses           NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    monthly_credit_liabilities NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    marital_status             VARCHAR(16)    NOT NULL DEFAULT '',
+    dependents                 INT            NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_idx ON users (email) WHERE deleted_at IS NULL;
+
+ALTER TABLE applications
+    ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users (id);
+
+CREATE INDEX IF NOT EXISTS applications_user_id_idx ON applications (user_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS applications_merchant_id_idx;
+
+ALTER TABLE applications
+    DROP COLUMN IF EXISTS merchant_id;
+
+DROP TABLE IF EXISTS merchant_api_key_usage;
+DROP TABLE IF EXISTS merchant_api_keys;
+DROP TABLE IF EXISTS merchants;
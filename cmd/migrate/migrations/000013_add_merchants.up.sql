@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS merchants
+(
+    id         UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    name       VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS merchant_api_keys
+(
+    id                UUID PRIMARY KEY,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    merchant_id       UUID        NOT NULL REFERENCES merchants (id),
+    prefix            VARCHAR(16) NOT NULL,
+    key_hash          VARCHAR(64) NOT NULL UNIQUE,
+    request_quota     INT         NOT NULL DEFAULT 0,
+    application_quota INT         NOT NULL DEFAULT 0,
+    revoked_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS merchant_api_keys_merchant_id_idx ON merchant_api_keys (merchant_id);
+
+CREATE TABLE IF NOT EXISTS merchant_api_key_usage
+(
+    api_key_id   UUID        NOT NULL REFERENCES merchant_api_keys (id),
+    kind         VARCHAR(16) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    count        INT         NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, kind, window_start)
+);
+
+ALTER TABLE applications
+    ADD COLUMN IF NOT EXISTS merchant_id UUID REFERENCES merchants (id);
+
+CREATE INDEX IF NOT EXISTS applications_merchant_id_idx ON applications (merchant_id);
@@ -0,0 +1,6 @@
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS merchant_id;
+
+ALTER TABLE merchants
+    DROP COLUMN IF EXISTS branding,
+    DROP COLUMN IF EXISTS banks;
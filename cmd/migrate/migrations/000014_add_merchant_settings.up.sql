@@ -0,0 +1,12 @@
+ALTER TABLE merchants
+    ADD COLUMN IF NOT EXISTS banks    JSONB,
+    ADD COLUMN IF NOT EXISTS branding JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE offers
+    ADD COLUMN IF NOT EXISTS merchant_id UUID REFERENCES merchants (id);
+
+UPDATE offers
+SET merchant_id = applications.merchant_id
+FROM applications
+WHERE applications.id = offers.application_id
+  AND applications.merchant_id IS NOT NULL;
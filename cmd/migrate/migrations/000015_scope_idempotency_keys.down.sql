@@ -0,0 +1,9 @@
+DELETE FROM idempotency_keys
+WHERE subject <> '';
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT IF EXISTS idempotency_keys_pkey,
+    ADD PRIMARY KEY (key);
+
+ALTER TABLE idempotency_keys
+    DROP COLUMN IF EXISTS subject;
@@ -0,0 +1,6 @@
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS subject VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT IF EXISTS idempotency_keys_pkey,
+    ADD PRIMARY KEY (subject, key);
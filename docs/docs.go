@@ -15,14 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/applications": {
+        "/admin/merchants": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body with application details, validates input, and creates a new application.",
+                "description": "Creates a merchant, whose checkout authenticates with API keys. Requires the admin scope.",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,25 +30,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "admin"
                 ],
-                "summary": "Submit a new financing application",
+                "summary": "Create a merchant",
                 "parameters": [
                     {
-                        "description": "Application request",
-                        "name": "application",
+                        "description": "Merchant request",
+                        "name": "merchant",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/exchange.ApplicationRequest"
+                            "$ref": "#/definitions/exchange.MerchantRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/exchange.ApplicationResponse"
+                            "$ref": "#/definitions/exchange.MerchantResponse"
                         }
                     },
                     "400": {
@@ -57,6 +57,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/exchange.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -66,25 +72,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/applications/{id}": {
+        "/admin/merchants/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns application details and offers for the given application ID.",
+                "description": "Returns the merchant with its banks and branding. Requires the admin scope.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "admin"
                 ],
-                "summary": "Get application by ID",
+                "summary": "Get a merchant",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Application ID",
+                        "description": "Merchant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -94,11 +100,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/exchange.ApplicationResponse"
+                            "$ref": "#/definitions/exchange.MerchantResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/exchange.ErrorResponse"
                         }
@@ -112,162 +124,1512 @@ const docTemplate = `{
                 }
             }
         },
-        "/ws/applications/{id}": {
-            "get": {
+        "/admin/merchants/{id}/api-keys": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upgrades the HTTP connection to a WebSocket and subscribes the client\nto real-time application updates. The client must provide the application ID\nas a URL parameter. The connection is kept open until the client disconnects or an error occurs.",
+                "description": "Generates an API key of the merchant with request and application quotas, 0 meaning\nno limit. The key is returned only in this response. Requires the admin scope.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "wss"
+                    "admin"
                 ],
-                "summary": "Upgrades the HTTP connection to a WebSocket",
+                "summary": "Create a merchant API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Application ID",
+                        "description": "Merchant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "API key request",
+                        "name": "apiKey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/exchange.APIKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.APIKeyResponse"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/exchange.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "exchange.ApplicationRequest": {
-            "type": "object",
-            "properties": {
-                "agreeToBeScored": {
-                    "type": "boolean"
-                },
-                "agreeToDataSharing": {
-                    "type": "boolean"
-                },
-                "amount": {
-                    "type": "number",
-                    "minimum": 0
-                },
-                "dependents": {
-                    "type": "integer",
-                    "minimum": 0
-                },
-                "email": {
-                    "type": "string"
-                },
-                "maritalStatus": {
-                    "type": "string",
-                    "enum": [
-                        "SINGLE",
-                        "MARRIED",
-                        "DIVORCED",
-                        "COHABITING"
-                    ]
-                },
-                "monthlyCreditLiabilities": {
-                    "type": "number",
-                    "minimum": 0
-                },
-                "monthlyExpenses": {
-                    "type": "number",
-                    "minimum": 0
-                },
-                "monthlyIncome": {
-                    "type": "number",
-                    "minimum": 0
-                },
-                "phone": {
-                    "type": "string"
-                }
-            }
         },
-        "exchange.ApplicationResponse": {
-            "type": "object",
-            "properties": {
-                "agreeToBeScored": {
-                    "type": "boolean"
-                },
-                "agreeToDataSharing": {
-                    "type": "boolean"
-                },
-                "amount": {
-                    "type": "number"
-                },
-                "dependents": {
-                    "type": "integer"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "maritalStatus": {
-                    "type": "string"
-                },
-                "monthlyCreditLiabilities": {
-                    "type": "number"
-                },
-                "monthlyExpenses": {
-                    "type": "number"
-                },
-                "monthlyIncome": {
-                    "type": "number"
-                },
-                "offers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/exchange.OfferResponse"
+        "/admin/merchants/{id}/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the API key, which cannot be used anymore. Requires the admin scope.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke a merchant API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Merchant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
                     }
-                },
-                "phone": {
-                    "type": "string"
                 }
             }
         },
-        "exchange.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
+        "/admin/merchants/{id}/settings": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the banks applications of the merchant are sent to and its branding. Banks\nmust be configured, their base URL and headers, e.g. credentials of the merchant,\noverride the bank config. All configured banks are used if the list is empty.\nHeader values are not returned. Requires the admin scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update merchant settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Merchant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merchant settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/exchange.MerchantSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.MerchantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "exchange.OfferResponse": {
-            "type": "object",
-            "properties": {
-                "annualPercentageRate": {
-                    "type": "number"
-                },
-                "firstRepaymentDate": {
-                    "type": "string"
-                },
-                "monthlyPaymentAmount": {
-                    "type": "number"
-                },
-                "numberOfPayments": {
-                    "type": "integer"
-                },
-                "totalRepaymentAmount": {
-                    "type": "number"
-                }
-            }
+        "/applications": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON body with application details, validates input by the rules of the\nmarket selected by the X-Market header, and creates a new application. Applications\nof logged-in users are linked to them, and fields left out are taken from their profile.\nApplications submitted with a merchant API key are recorded for the merchant, sent to\nthe banks of the merchant and, once created, count against the application quota of the key.\nOther tokens, except admin ones, cannot submit applications as they could not read them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Submit a new financing application",
+                "parameters": [
+                    {
+                        "enum": [
+                            "LV",
+                            "LT",
+                            "EE"
+                        ],
+                        "type": "string",
+                        "description": "Market code, the configured default market if empty",
+                        "name": "X-Market",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Application request",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ApplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/applications/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns application details and offers for the given application ID. Offers are\nranked from best to worst and the best one is marked as recommended. Only the user\nor merchant that submitted the application, or an admin, can read it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get application by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "apr",
+                            "totalRepayment",
+                            "monthlyPayment"
+                        ],
+                        "type": "string",
+                        "description": "Criterion to sort offers by",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/applications/{id}/offers/{offerId}/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the repayment schedule of a processed offer: due dates starting at the first\nrepayment date, principal and interest parts of every payment and the remaining balance.\nOnly the user or merchant that submitted the application, or an admin, can read it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get offer repayment schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offer ID",
+                        "name": "offerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ScheduleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/applications/{id}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns submission, bank response, offer status change and broadcast events\nof the application in chronological order. Only the user or merchant that\nsubmitted the application, or an admin, can read it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get application timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.TimelineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Checks the email and password of a user and returns a bearer token for the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Log in",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/exchange.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Creates a user with the email, password and applicant profile. Profile fields are\nused for applications the user submits without them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a user",
+                "parameters": [
+                    {
+                        "description": "Registration request",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/exchange.RegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchants/me": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns the merchant the API key belongs to, with its banks and branding.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchants"
+                ],
+                "summary": "Get the authenticated merchant",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.MerchantResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user the bearer token was issued to, with the applicant profile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the logged-in user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.UserResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/profile": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the applicant profile of the logged-in user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update the applicant profile",
+                "parameters": [
+                    {
+                        "description": "Applicant profile",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/banks/{bank}": {
+            "post": {
+                "description": "Accepts a status change callback from a bank. The raw body must be signed with\nHMAC-SHA256 using the bank webhook secret and the hex-encoded signature passed in\nthe X-Signature header. The payload has the same shape as the bank status response.\nBanks push to the merchant URL for offers created with the base URL or headers of a\nmerchant, i.e. with the account the merchant has with the bank.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Receive application status update from a bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank name",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 signature of the body",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/merchants/{merchantId}/banks/{bank}": {
+            "post": {
+                "description": "Accepts a status change callback from a bank. The raw body must be signed with\nHMAC-SHA256 using the bank webhook secret and the hex-encoded signature passed in\nthe X-Signature header. The payload has the same shape as the bank status response.\nBanks push to the merchant URL for offers created with the base URL or headers of a\nmerchant, i.e. with the account the merchant has with the bank.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Receive application status update from a bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank name",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merchant ID, for merchant accounts at the bank",
+                        "name": "merchantId",
+                        "in": "path"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 signature of the body",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upgrades the HTTP connection to a WebSocket following any number of applications.\nClients send JSON commands: {\"type\": \"subscribe\", \"id\": \"1\", \"applicationId\": \"...\"},\n{\"type\": \"unsubscribe\", \"id\": \"2\", \"applicationId\": \"...\"} and {\"type\": \"ping\", \"id\": \"3\"}.\nEvery command is answered with an \"ack\", \"pong\" or \"error\" message carrying the command ID.\nOffer updates are sent as {\"type\": \"offer\", \"applicationId\": \"...\", \"offer\": {...}}.\nOnly the user or merchant that submitted an application, or an admin, can subscribe to it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wss"
+                ],
+                "summary": "Upgrades the HTTP connection to a multiplexed WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token, if the Authorization header cannot be set",
+                        "name": "access_token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Commands sent over the connection",
+                        "name": "command",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.WebSocketCommand"
+                        }
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.WebSocketMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/applications/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upgrades the HTTP connection to a WebSocket and subscribes the client\nto real-time application updates. The client must provide the application ID\nas a URL parameter. The connection is kept open until the client disconnects or an error occurs.\nOnly the user or merchant that submitted the application, or an admin, can subscribe.\nUse /ws to follow several applications over one connection.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wss"
+                ],
+                "summary": "Upgrades the HTTP connection to a WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token, if the Authorization header cannot be set",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/exchange.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "exchange.APIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "applicationQuota": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "requestQuota": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "exchange.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "applicationQuota": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is returned only once, when the key is created.",
+                    "type": "string"
+                },
+                "merchantId": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "requestQuota": {
+                    "type": "integer"
+                }
+            }
+        },
+        "exchange.ApplicationRequest": {
+            "type": "object",
+            "properties": {
+                "agreeToBeScored": {
+                    "type": "boolean"
+                },
+                "agreeToDataSharing": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "dependents": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "email": {
+                    "type": "string"
+                },
+                "maritalStatus": {
+                    "type": "string",
+                    "enum": [
+                        "SINGLE",
+                        "MARRIED",
+                        "DIVORCED",
+                        "COHABITING"
+                    ]
+                },
+                "monthlyCreditLiabilities": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "monthlyExpenses": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "monthlyIncome": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.ApplicationResponse": {
+            "type": "object",
+            "properties": {
+                "agreeToBeScored": {
+                    "type": "boolean"
+                },
+                "agreeToDataSharing": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "dependents": {
+                    "type": "integer"
+                },
+                "duplicate": {
+                    "description": "Duplicate is set if the submitted application duplicates this, previously submitted, one.",
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "maritalStatus": {
+                    "type": "string"
+                },
+                "merchantId": {
+                    "type": "string"
+                },
+                "monthlyCreditLiabilities": {
+                    "type": "number"
+                },
+                "monthlyExpenses": {
+                    "type": "number"
+                },
+                "monthlyIncome": {
+                    "type": "number"
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exchange.OfferResponse"
+                    }
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.BrandingRequest": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "logoUrl": {
+                    "type": "string"
+                },
+                "primaryColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.BrandingResponse": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "logoUrl": {
+                    "type": "string"
+                },
+                "primaryColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.InstallmentResponse": {
+            "type": "object",
+            "properties": {
+                "dueDate": {
+                    "type": "string"
+                },
+                "interest": {
+                    "type": "number"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "payment": {
+                    "type": "number"
+                },
+                "principal": {
+                    "type": "number"
+                },
+                "remainingBalance": {
+                    "type": "number"
+                }
+            }
+        },
+        "exchange.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.MerchantBankRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "baseUrl": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.MerchantBankResponse": {
+            "type": "object",
+            "properties": {
+                "baseUrl": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.MerchantRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "exchange.MerchantResponse": {
+            "type": "object",
+            "properties": {
+                "banks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exchange.MerchantBankResponse"
+                    }
+                },
+                "branding": {
+                    "$ref": "#/definitions/exchange.BrandingResponse"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.MerchantSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "banks": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/exchange.MerchantBankRequest"
+                    }
+                },
+                "branding": {
+                    "$ref": "#/definitions/exchange.BrandingRequest"
+                }
+            }
+        },
+        "exchange.OfferResponse": {
+            "type": "object",
+            "properties": {
+                "annualPercentageRate": {
+                    "type": "number"
+                },
+                "bank": {
+                    "type": "string"
+                },
+                "computedAnnualPercentageRate": {
+                    "type": "number"
+                },
+                "computedTotalRepaymentAmount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "firstRepaymentDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "monthlyPaymentAmount": {
+                    "type": "number"
+                },
+                "numberOfPayments": {
+                    "type": "integer"
+                },
+                "recommended": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "totalRepaymentAmount": {
+                    "type": "number"
+                },
+                "verification": {
+                    "description": "Verification is VERIFIED or MISMATCH if the stated figures diverge from computed ones.",
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.ProfileRequest": {
+            "type": "object",
+            "properties": {
+                "dependents": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "maritalStatus": {
+                    "type": "string",
+                    "enum": [
+                        "SINGLE",
+                        "MARRIED",
+                        "DIVORCED",
+                        "COHABITING"
+                    ]
+                },
+                "monthlyCreditLiabilities": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "monthlyExpenses": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "monthlyIncome": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "dependents": {
+                    "type": "integer"
+                },
+                "maritalStatus": {
+                    "type": "string"
+                },
+                "monthlyCreditLiabilities": {
+                    "type": "number"
+                },
+                "monthlyExpenses": {
+                    "type": "number"
+                },
+                "monthlyIncome": {
+                    "type": "number"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.RegistrationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "profile": {
+                    "$ref": "#/definitions/exchange.ProfileRequest"
+                }
+            }
+        },
+        "exchange.ScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exchange.InstallmentResponse"
+                    }
+                },
+                "offerId": {
+                    "type": "string"
+                },
+                "totalInterest": {
+                    "type": "number"
+                },
+                "totalPayment": {
+                    "type": "number"
+                }
+            }
+        },
+        "exchange.TimelineEventResponse": {
+            "type": "object",
+            "properties": {
+                "bank": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "offerId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.TimelineResponse": {
+            "type": "object",
+            "properties": {
+                "applicationId": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exchange.TimelineEventResponse"
+                    }
+                }
+            }
+        },
+        "exchange.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "tokenType": {
+                    "type": "string"
+                }
+            }
+        },
+        "exchange.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profile": {
+                    "$ref": "#/definitions/exchange.ProfileResponse"
+                }
+            }
+        },
+        "exchange.WebSocketCommand": {
+            "type": "object",
+            "properties": {
+                "applicationId": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is echoed in the reply, so that clients can match replies to commands.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "subscribe",
+                        "unsubscribe",
+                        "ping"
+                    ]
+                }
+            }
+        },
+        "exchange.WebSocketMessage": {
+            "type": "object",
+            "properties": {
+                "applicationId": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "offer": {
+                    "$ref": "#/definitions/exchange.OfferResponse"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "ack",
+                        "pong",
+                        "error",
+                        "offer"
+                    ]
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "APIKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "type": "apiKey",
             "name": "Authorization",
@@ -0,0 +1,72 @@
+package amortization
+
+import (
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"math"
+	"time"
+)
+
+// MonthlyRate returns the monthly interest rate at which numberOfPayments equal monthly
+// payments repay the principal. The rate is found by bisection, as the present value of
+// the payments decreases monotonically with the rate.
+func MonthlyRate(principal, payment float64, numberOfPayments int) float64 {
+	if principal <= 0 || payment <= 0 || numberOfPayments <= 0 {
+		return 0
+	}
+
+	presentValue := func(rate float64) float64 {
+		if rate == 0 {
+			return payment * float64(numberOfPayments)
+		}
+		return payment * (1 - math.Pow(1+rate, -float64(numberOfPayments))) / rate
+	}
+
+	low, high := -0.99, 1.0
+	for range 200 {
+		mid := (low + high) / 2
+		if presentValue(mid) > principal {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// Schedule splits numberOfPayments monthly payments repaying the principal into interest
+// and principal parts. Payments are due monthly starting at firstRepaymentDate, and the
+// last payment is adjusted to repay the remaining balance left by rounding.
+func Schedule(principal, payment money.Amount, numberOfPayments int, firstRepaymentDate time.Time) []dto.InstallmentDTO {
+	rate := MonthlyRate(principal.Float64(), payment.Float64(), numberOfPayments)
+	balance := principal
+
+	installments := make([]dto.InstallmentDTO, 0, numberOfPayments)
+	for i := range numberOfPayments {
+		interest := balance.Mul(rate)
+		amount := payment
+		if i == numberOfPayments-1 {
+			amount = balance + interest
+		}
+		repaid := amount - interest
+		balance -= repaid
+
+		installments = append(installments, dto.InstallmentDTO{
+			Number:           i + 1,
+			DueDate:          addMonths(firstRepaymentDate, i),
+			Payment:          amount,
+			Principal:        repaid,
+			Interest:         interest,
+			RemainingBalance: balance,
+		})
+	}
+	return installments
+}
+
+// addMonths adds months to the date, moving it to the last day of the month if the month
+// is shorter, e.g. January 31 is followed by February 28.
+func addMonths(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, date.Location()).Day()
+	return time.Date(year, month+time.Month(months), min(day, lastDay), 0, 0, 0, 0, date.Location())
+}
@@ -0,0 +1,54 @@
+package amortization
+
+import (
+	"financing-aggregator/internal/money"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type amortizationTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(amortizationTestSuite))
+}
+
+func (s *amortizationTestSuite) Test_MonthlyRate() {
+	s.InDelta(0.01, MonthlyRate(1000, 88.848789, 12), 1e-6)
+	s.InDelta(0, MonthlyRate(1200, 100, 12), 1e-6)
+	s.Zero(MonthlyRate(1000, 0, 0))
+}
+
+func (s *amortizationTestSuite) Test_Schedule() {
+	firstRepaymentDate := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	s.Run("payments split into principal and interest", func() {
+		actual := Schedule(money.MustParse("1000"), money.MustParse("88.85"), 12, firstRepaymentDate)
+		s.Len(actual, 12)
+
+		s.Equal(1, actual[0].Number)
+		s.Equal(firstRepaymentDate, actual[0].DueDate)
+		s.Equal(money.MustParse("88.85"), actual[0].Payment)
+		s.Equal(money.MustParse("10"), actual[0].Interest)
+		s.Equal(money.MustParse("78.85"), actual[0].Principal)
+		s.Equal(money.MustParse("921.15"), actual[0].RemainingBalance)
+
+		var principal money.Amount
+		for _, installment := range actual {
+			principal += installment.Principal
+			s.Equal(installment.Payment, installment.Principal+installment.Interest)
+		}
+		s.Equal(money.MustParse("1000"), principal)
+		s.Zero(actual[11].RemainingBalance)
+		s.InDelta(88.85, actual[11].Payment.Float64(), 0.05)
+	})
+
+	s.Run("due dates kept within shorter months", func() {
+		actual := Schedule(money.MustParse("300"), money.MustParse("100"), 3, firstRepaymentDate)
+		s.Equal(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), actual[1].DueDate)
+		s.Equal(time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC), actual[2].DueDate)
+		s.Equal([]money.Amount{0, 0, 0}, []money.Amount{actual[0].Interest, actual[1].Interest, actual[2].Interest})
+	})
+}
@@ -2,13 +2,14 @@ package app
 
 import (
 	"context"
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/banks"
-	"financing-aggregator/internal/banks/fastbank"
-	"financing-aggregator/internal/banks/solidbank"
+	"financing-aggregator/internal/banks/declarative"
 	"financing-aggregator/internal/config"
 	"financing-aggregator/internal/controllers"
 	httpHandlers "financing-aggregator/internal/controllers/http"
 	"financing-aggregator/internal/controllers/ws"
+	"financing-aggregator/internal/ranking"
 	"financing-aggregator/internal/repositories"
 	"financing-aggregator/internal/services"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"net/http"
+	"os"
 )
 
 type App struct {
@@ -35,6 +37,11 @@ func New(cfg *config.Config, logger *zap.Logger, db *gorm.DB) (*App, error) {
 		return nil, errors.Errorf("failed to create scheduler: %s", err.Error())
 	}
 
+	if cfg.Queue.WorkerID == "" {
+		hostname, _ := os.Hostname()
+		cfg.Queue.WorkerID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
 	return &App{
 		cfg:    cfg,
 		logger: logger,
@@ -50,21 +57,78 @@ func (a *App) Run() error {
 		}
 	}()
 
-	fastBank := fastbank.NewFastBank(a.cfg.Banks.FastBankURL)
-	solidBank := solidbank.NewSolidBank(a.cfg.Banks.SolidBankURL)
+	allBanks, err := a.newBanks()
+	if err != nil {
+		return fmt.Errorf("failed to create banks: %v", err)
+	}
+
+	ranker, err := ranking.New(a.cfg.Ranking.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to create offer ranker: %v", err)
+	}
+
+	markets, err := httpHandlers.NewMarketValidator(a.cfg.Markets)
+	if err != nil {
+		return fmt.Errorf("failed to create market validator: %v", err)
+	}
+
+	verifier, err := auth.New(a.cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("failed to create token verifier: %v", err)
+	}
 
 	applicationRepository := repositories.NewApplicationRepository(a.db)
 	offerRepository := repositories.NewOfferRepository(a.db)
-
-	wsHandler := ws.NewWebSocketHandler(a.logger)
+	submissionRepository := repositories.NewSubmissionRepository(a.db)
+	offerEventRepository := repositories.NewOfferEventRepository(a.db)
+	idempotencyKeyRepository := repositories.NewIdempotencyKeyRepository(a.db)
+	userRepository := repositories.NewUserRepository(a.db)
+	merchantRepository := repositories.NewMerchantRepository(a.db)
+	offerUpdateRepository := repositories.NewOfferUpdateRepository(a.db)
+
+	wsHandler := ws.NewWebSocketHandler(a.logger, applicationRepository, offerUpdateRepository)
 	defer wsHandler.CloseAll()
 
-	applicationService := services.NewApplicationService(a.logger, []banks.Bank{fastBank, solidBank}, applicationRepository, offerRepository, wsHandler)
-	applicationHandler := httpHandlers.NewApplicationHandler(applicationService)
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
+	go wsHandler.ListenForOffers(listenCtx)
+
+	applicationService, err := services.NewApplicationService(
+		a.logger,
+		a.cfg,
+		allBanks,
+		a.newBank,
+		ranker,
+		applicationRepository,
+		offerRepository,
+		submissionRepository,
+		offerEventRepository,
+		merchantRepository,
+		wsHandler,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create application service: %v", err)
+	}
+	idempotencyService := services.NewIdempotencyService(a.logger, a.cfg, idempotencyKeyRepository)
+	userService := services.NewUserService(a.logger, auth.NewIssuer(a.cfg.Auth), userRepository)
+	merchantService := services.NewMerchantService(a.logger, a.cfg, merchantRepository)
+	applicationHandler := httpHandlers.NewApplicationHandler(applicationService, userService, merchantService, markets)
+	userHandler := httpHandlers.NewUserHandler(userService)
+	merchantHandler := httpHandlers.NewMerchantHandler(merchantService)
+	webhookHandler := httpHandlers.NewWebhookHandler(applicationService)
 
 	if err := a.registerCronJob("check offers", a.cfg.CronTabs.CheckOffersCronTab, applicationService.UpdateApplicationStatuses); err != nil {
 		return fmt.Errorf("failed to register cron job: %v", err)
 	}
+	if err := a.registerCronJob("process submissions", a.cfg.CronTabs.ProcessSubmissionsCronTab, applicationService.ProcessSubmissions); err != nil {
+		return fmt.Errorf("failed to register cron job: %v", err)
+	}
+	if err := a.registerCronJob("expire offers", a.cfg.CronTabs.ExpireOffersCronTab, applicationService.ExpireOffers); err != nil {
+		return fmt.Errorf("failed to register cron job: %v", err)
+	}
+	if err := a.registerCronJob("purge quota usage", a.cfg.CronTabs.PurgeQuotaUsageCronTab, merchantService.PurgeQuotaUsage); err != nil {
+		return fmt.Errorf("failed to register cron job: %v", err)
+	}
 
 	a.cron.Start()
 
@@ -78,14 +142,38 @@ func (a *App) Run() error {
 	}
 
 	r.GET("/healthz", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		bankStates := make(map[string]string, len(allBanks))
+		for _, bank := range allBanks {
+			if checker, ok := bank.(banks.HealthChecker); ok {
+				bankStates[bank.Name()] = checker.Health()
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "banks": bankStates})
 	})
 
-	r.Use(controllers.AuthMiddleware())
+	r.POST("/webhooks/banks/:bank", webhookHandler.ReceiveBankWebhook)
+	r.POST("/webhooks/merchants/:merchantId/banks/:bank", webhookHandler.ReceiveBankWebhook)
+	r.POST("/api/auth/register", userHandler.Register)
+	r.POST("/api/auth/login", userHandler.Login)
+
+	r.Use(controllers.AuthMiddleware(verifier, merchantService))
 
+	r.GET("/ws", wsHandler.Connect)
 	r.GET("/ws/applications/:id", wsHandler.SubscribeToApplicationUpdates)
-	r.POST("/api/applications", applicationHandler.SubmitApplication)
+	r.POST("/api/applications", controllers.IdempotencyMiddleware(idempotencyService), applicationHandler.SubmitApplication)
 	r.GET("/api/applications/:id", applicationHandler.GetApplication)
+	r.GET("/api/applications/:id/timeline", applicationHandler.GetApplicationTimeline)
+	r.GET("/api/applications/:id/offers/:offerId/schedule", applicationHandler.GetOfferSchedule)
+	r.GET("/api/users/me", userHandler.GetCurrentUser)
+	r.PUT("/api/users/me/profile", userHandler.UpdateProfile)
+	r.GET("/api/merchants/me", merchantHandler.GetCurrentMerchant)
+
+	admin := r.Group("/api/admin", controllers.RequireScope(auth.ScopeAdmin))
+	admin.POST("/merchants", merchantHandler.CreateMerchant)
+	admin.GET("/merchants/:id", merchantHandler.GetMerchant)
+	admin.PUT("/merchants/:id/settings", merchantHandler.UpdateSettings)
+	admin.POST("/merchants/:id/api-keys", merchantHandler.CreateAPIKey)
+	admin.DELETE("/merchants/:id/api-keys/:keyId", merchantHandler.RevokeAPIKey)
 
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%d", a.cfg.Port),
@@ -111,3 +199,21 @@ func (a *App) Stop(ctx context.Context) error {
 
 	return a.srv.Shutdown(ctx)
 }
+
+func (a *App) newBanks() ([]banks.Bank, error) {
+	allBanks := make([]banks.Bank, 0, len(a.cfg.Banks))
+	for name, bankCfg := range a.cfg.Banks {
+		bank, err := a.newBank(name, bankCfg)
+		if err != nil {
+			return nil, err
+		}
+		allBanks = append(allBanks, bank)
+	}
+
+	return allBanks, nil
+}
+
+// newBank creates a bank from its config. It is also used for banks with settings of a merchant.
+func (a *App) newBank(name string, cfg config.BankConfig) (banks.Bank, error) {
+	return declarative.NewBank(name, cfg, a.logger)
+}
@@ -17,6 +17,7 @@ func (a *App) registerCronJob(name, schedule string, task func(context.Context))
 		gocron.CronJob(schedule, true),
 		gocron.NewTask(a.taskWrapper(name, task)),
 		gocron.WithName(name),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
 	)
 	if err != nil {
 		return errors.Errorf("error register %s masterjob: %s", name, err.Error())
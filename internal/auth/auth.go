@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"financing-aggregator/internal/config"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PrincipalKey is the gin context key the authenticated principal is stored under.
+	PrincipalKey = "auth.principal"
+
+	// ScopeUser is granted to tokens issued to registered users, whose subject is the user ID.
+	ScopeUser = "user"
+	// ScopeMerchant is granted to merchants authenticated with an API key, whose subject is
+	// the merchant ID. It is never taken from tokens, which are not subject to key quotas.
+	ScopeMerchant = "merchant"
+	// ScopeAdmin grants access to administrative endpoints.
+	ScopeAdmin = "admin"
+)
+
+// minHS256SecretLength is the key size of HS256 in bytes. Shorter secrets, like placeholders
+// left in configs, can be guessed or brute-forced.
+const minHS256SecretLength = 32
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// Principal is the caller identified by a verified token.
+type Principal struct {
+	Subject string
+	Scopes  []string
+	// APIKeyID is the ID of the merchant API key the principal authenticated with.
+	APIKeyID string
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// UserID returns the subject of a token issued to a registered user.
+func (p Principal) UserID() (string, bool) {
+	if !p.HasScope(ScopeUser) {
+		return "", false
+	}
+	return p.Subject, true
+}
+
+// MerchantID returns the subject of a merchant authenticated with an API key.
+func (p Principal) MerchantID() (string, bool) {
+	if !p.HasScope(ScopeMerchant) {
+		return "", false
+	}
+	return p.Subject, true
+}
+
+// CanAccess reports whether the principal may read an application submitted through the
+// merchant by the user, either nil if the application has none. Users and merchants only see
+// their own applications, principals with the admin scope see all of them.
+func (p Principal) CanAccess(merchantID, userID *uuid.UUID) bool {
+	if p.HasScope(ScopeAdmin) {
+		return true
+	}
+	if id, ok := p.MerchantID(); ok && merchantID != nil && id == merchantID.String() {
+		return true
+	}
+	if id, ok := p.UserID(); ok && userID != nil && id == userID.String() {
+		return true
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by NewContext, or stored in a gin context
+// under PrincipalKey.
+func FromContext(ctx context.Context) (Principal, bool) {
+	if p, ok := ctx.Value(principalKey{}).(Principal); ok {
+		return p, true
+	}
+	p, ok := ctx.Value(PrincipalKey).(Principal)
+	return p, ok
+}
+
+// claims are the token claims a principal is built from. Scopes are read from the
+// space-separated "scope" claim or from the "scp" array.
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope,omitempty"`
+	Scp   []string `json:"scp,omitempty"`
+}
+
+// Verifier verifies signatures, issuer, audience and expiry of bearer tokens. HS256 tokens
+// are verified with the configured secret, RS256 and ES256 tokens with keys from the JWKS.
+type Verifier struct {
+	secret []byte
+	keys   *keySet
+	parser *jwt.Parser
+}
+
+func New(cfg config.AuthConfig) (*Verifier, error) {
+	v := &Verifier{}
+
+	var methods []string
+	if cfg.HS256Secret != "" {
+		if len(cfg.HS256Secret) < minHS256SecretLength {
+			return nil, errors.Errorf("HS256 secret must be a random value of at least %d bytes", minHS256SecretLength)
+		}
+		v.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	switch {
+	case cfg.JWKSFile != "" && cfg.JWKSURL != "":
+		return nil, errors.New("only one of JWKS file and URL can be set")
+	case cfg.JWKSFile != "":
+		keys, err := newFileKeySet(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+	case cfg.JWKSURL != "":
+		keys, err := newURLKeySet(cfg.JWKSURL, cfg.JWKSRefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+	}
+	if v.keys != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("neither HS256 secret nor JWKS is configured")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(options...)
+
+	return v, nil
+}
+
+// Verify checks the token and returns the principal it was issued to.
+func (v *Verifier) Verify(ctx context.Context, token string) (Principal, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(token, &c, v.keyFunc(ctx)); err != nil {
+		return Principal{}, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+	if c.Subject == "" {
+		return Principal{}, errors.Wrap(ErrInvalidToken, "token has no subject")
+	}
+
+	scopes := strings.Fields(c.Scope)
+	for _, scope := range c.Scp {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	scopes = slices.DeleteFunc(scopes, func(scope string) bool { return scope == ScopeMerchant })
+
+	return Principal{
+		Subject: c.Subject,
+		Scopes:  scopes,
+	}, nil
+}
+
+func (v *Verifier) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return v.secret, nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.get(ctx, kid)
+	}
+}
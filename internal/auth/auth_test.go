@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"financing-aggregator/internal/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "a-test-secret-of-at-least-32-bytes"
+
+type verifierTestSuite struct {
+	suite.Suite
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(verifierTestSuite))
+}
+
+func (s *verifierTestSuite) SetupSuite() {
+	var err error
+	s.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+}
+
+func (s *verifierTestSuite) Test_Verify_HS256() {
+	verifier, err := New(getTestAuthConfig())
+	s.Require().NoError(err)
+
+	s.Run("valid token", func() {
+		claims := getTestClaims()
+		claims["scope"] = "applications:read applications:write"
+
+		actual, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.NoError(err)
+		s.Equal(Principal{Subject: "anakin", Scopes: []string{"applications:read", "applications:write"}}, actual)
+		s.True(actual.HasScope("applications:write"))
+	})
+
+	s.Run("scopes read from scp array", func() {
+		claims := getTestClaims()
+		claims["scp"] = []string{"applications:read"}
+
+		actual, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.NoError(err)
+		s.Equal([]string{"applications:read"}, actual.Scopes)
+	})
+
+	s.Run("merchant scope dropped", func() {
+		claims := getTestClaims()
+		claims["scope"] = "merchant applications:read"
+		claims["scp"] = []string{"merchant"}
+
+		actual, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.NoError(err)
+		s.Equal([]string{"applications:read"}, actual.Scopes)
+		s.False(actual.HasScope(ScopeMerchant))
+	})
+
+	s.Run("error occurs because secret differs", func() {
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte("other"), getTestClaims()))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because token expired", func() {
+		claims := getTestClaims()
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because expiry is missing", func() {
+		claims := getTestClaims()
+		delete(claims, "exp")
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because issuer differs", func() {
+		claims := getTestClaims()
+		claims["iss"] = "sith"
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because audience differs", func() {
+		claims := getTestClaims()
+		claims["aud"] = "other-api"
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because subject is missing", func() {
+		claims := getTestClaims()
+		delete(claims, "sub")
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because RS256 is not configured", func() {
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, getTestClaims()))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+}
+
+func (s *verifierTestSuite) Test_Verify_JWKSFile() {
+	path := filepath.Join(s.T().TempDir(), "jwks.json")
+	s.Require().NoError(os.WriteFile(path, s.jwks("rsa-1", "ec-1"), 0o600))
+
+	cfg := getTestAuthConfig()
+	cfg.HS256Secret = ""
+	cfg.JWKSFile = path
+	verifier, err := New(cfg)
+	s.Require().NoError(err)
+
+	s.Run("RS256 token verified", func() {
+		actual, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, getTestClaims()))
+		s.NoError(err)
+		s.Equal("anakin", actual.Subject)
+	})
+
+	s.Run("ES256 token verified", func() {
+		actual, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodES256, "ec-1", s.ecKey, getTestClaims()))
+		s.NoError(err)
+		s.Equal("anakin", actual.Subject)
+	})
+
+	s.Run("error occurs because key is unknown", func() {
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodRS256, "rsa-2", s.rsaKey, getTestClaims()))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+
+	s.Run("error occurs because HS256 is not configured", func() {
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodHS256, "", []byte(testSecret), getTestClaims()))
+		s.ErrorIs(err, ErrInvalidToken)
+	})
+}
+
+func (s *verifierTestSuite) Test_Verify_JWKSURL() {
+	kid := "rsa-1"
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write(s.jwks(kid, "ec-1"))
+	}))
+	defer server.Close()
+
+	cfg := getTestAuthConfig()
+	cfg.JWKSURL = server.URL
+	cfg.JWKSRefreshInterval = time.Nanosecond
+	verifier, err := New(cfg)
+	s.Require().NoError(err)
+	s.Equal(1, fetches)
+
+	s.Run("token verified with fetched key", func() {
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, getTestClaims()))
+		s.NoError(err)
+		s.Equal(1, fetches)
+	})
+
+	s.Run("rotated key fetched", func() {
+		kid = "rsa-2"
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodRS256, "rsa-2", s.rsaKey, getTestClaims()))
+		s.NoError(err)
+		s.Equal(2, fetches)
+	})
+
+	s.Run("keys not fetched again within refresh interval", func() {
+		verifier.keys.refreshInterval = time.Hour
+
+		_, err := verifier.Verify(context.Background(), s.sign(jwt.SigningMethodRS256, "rsa-3", s.rsaKey, getTestClaims()))
+		s.ErrorIs(err, ErrInvalidToken)
+		s.Equal(2, fetches)
+	})
+}
+
+func (s *verifierTestSuite) Test_New() {
+	s.Run("error occurs because no keys are configured", func() {
+		cfg := getTestAuthConfig()
+		cfg.HS256Secret = ""
+
+		_, err := New(cfg)
+		s.Error(err)
+	})
+
+	s.Run("error occurs because HS256 secret is too short", func() {
+		cfg := getTestAuthConfig()
+		cfg.HS256Secret = "dev-secret-change-me"
+
+		_, err := New(cfg)
+		s.ErrorContains(err, "at least 32 bytes")
+	})
+
+	s.Run("error occurs because JWKS has no signing keys", func() {
+		path := filepath.Join(s.T().TempDir(), "jwks.json")
+		s.Require().NoError(os.WriteFile(path, []byte(`{"keys":[{"kty":"oct","kid":"1","k":"c2VjcmV0"}]}`), 0o600))
+
+		cfg := getTestAuthConfig()
+		cfg.JWKSFile = path
+
+		_, err := New(cfg)
+		s.ErrorContains(err, "no signing keys")
+	})
+}
+
+func (s *verifierTestSuite) Test_FromContext() {
+	expected := Principal{Subject: "anakin"}
+
+	actual, ok := FromContext(NewContext(context.Background(), expected))
+	s.True(ok)
+	s.Equal(expected, actual)
+
+	_, ok = FromContext(context.Background())
+	s.False(ok)
+}
+
+func (s *verifierTestSuite) Test_CanAccess() {
+	merchantID, userID := uuid.New(), uuid.New()
+
+	s.Run("owners and admins can access", func() {
+		merchant := Principal{Subject: merchantID.String(), Scopes: []string{ScopeMerchant}}
+		s.True(merchant.CanAccess(&merchantID, nil))
+		s.True(merchant.CanAccess(&merchantID, &userID))
+
+		user := Principal{Subject: userID.String(), Scopes: []string{ScopeUser}}
+		s.True(user.CanAccess(nil, &userID))
+
+		admin := Principal{Subject: "obi-wan", Scopes: []string{ScopeAdmin}}
+		s.True(admin.CanAccess(nil, nil))
+		s.True(admin.CanAccess(&merchantID, &userID))
+	})
+
+	s.Run("others cannot access", func() {
+		merchant := Principal{Subject: uuid.NewString(), Scopes: []string{ScopeMerchant}}
+		s.False(merchant.CanAccess(&merchantID, nil))
+		s.False(merchant.CanAccess(nil, nil))
+
+		user := Principal{Subject: uuid.NewString(), Scopes: []string{ScopeUser}}
+		s.False(user.CanAccess(nil, &userID))
+
+		// A user ID held by a merchant, or a merchant ID by a user, does not grant access.
+		s.False(Principal{Subject: userID.String(), Scopes: []string{ScopeMerchant}}.CanAccess(nil, &userID))
+		s.False(Principal{Subject: merchantID.String(), Scopes: []string{ScopeUser}}.CanAccess(&merchantID, nil))
+
+		s.False(Principal{Subject: "anakin"}.CanAccess(nil, nil))
+	})
+}
+
+func (s *verifierTestSuite) sign(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	s.Require().NoError(err)
+	return signed
+}
+
+func (s *verifierTestSuite) jwks(rsaKID, ecKID string) []byte {
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": rsaKID,
+				"use": "sig",
+				"n":   encode(s.rsaKey.N),
+				"e":   encode(big.NewInt(int64(s.rsaKey.E))),
+			},
+			{
+				"kty": "EC",
+				"kid": ecKID,
+				"crv": "P-256",
+				"x":   encode(s.ecKey.X),
+				"y":   encode(s.ecKey.Y),
+			},
+		},
+	})
+	s.Require().NoError(err)
+	return data
+}
+
+func getTestAuthConfig() config.AuthConfig {
+	return config.AuthConfig{
+		Issuer:      "financing-aggregator",
+		Audience:    "financing-aggregator-api",
+		HS256Secret: testSecret,
+		Leeway:      time.Second,
+	}
+}
+
+func getTestClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "anakin",
+		"iss": "financing-aggregator",
+		"aud": "financing-aggregator-api",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"financing-aggregator/internal/config"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+const defaultTokenTTL = time.Hour
+
+var ErrIssuingDisabled = errors.New("issuing tokens requires an HS256 secret")
+
+// Issuer signs HS256 tokens accepted by a Verifier with the same config.
+type Issuer struct {
+	secret   []byte
+	issuer   string
+	audience string
+	ttl      time.Duration
+}
+
+func NewIssuer(cfg config.AuthConfig) *Issuer {
+	ttl := cfg.TokenTTL
+	if ttl <= 0 {
+		ttl = defaultTokenTTL
+	}
+	return &Issuer{
+		secret:   []byte(cfg.HS256Secret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		ttl:      ttl,
+	}
+}
+
+// Issue returns a token of the subject with the scopes and the time it expires at.
+func (i *Issuer) Issue(subject string, scopes []string) (string, time.Time, error) {
+	if len(i.secret) == 0 {
+		return "", time.Time{}, ErrIssuingDisabled
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(i.ttl)
+	registered := jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    i.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	if i.audience != "" {
+		registered.Audience = jwt.ClaimStrings{i.audience}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: registered,
+		Scope:            strings.Join(scopes, " "),
+	}).SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "failed to sign token")
+	}
+	return token, expiresAt, nil
+}
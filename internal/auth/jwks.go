@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	jwksFetchTimeout           = 10 * time.Second
+	defaultJWKSRefreshInterval = 5 * time.Minute
+)
+
+// keySet holds public keys of a JWKS keyed by key ID. Keys read from a URL are fetched
+// again when a token is signed with an unknown key, at most once per refresh interval,
+// so that rotated keys are picked up.
+type keySet struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+func newFileKeySet(path string) (*keySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read JWKS file")
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &keySet{keys: keys}, nil
+}
+
+func newURLKeySet(url string, refreshInterval time.Duration) (*keySet, error) {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval
+	}
+	ks := &keySet{
+		url:             url,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+		refreshInterval: refreshInterval,
+	}
+	if err := ks.fetch(context.Background()); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (ks *keySet) get(ctx context.Context, kid string) (any, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	if ks.url == "" || time.Since(ks.fetchedAt) < ks.refreshInterval {
+		return nil, errors.Wrap(ErrUnknownKey, kid)
+	}
+	if err := ks.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, errors.Wrap(ErrUnknownKey, kid)
+}
+
+// lookup returns the key with the ID. Tokens without a key ID are accepted only if the
+// set holds a single key.
+func (ks *keySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+func (ks *keySet) fetch(ctx context.Context) error {
+	ks.fetchedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create JWKS request")
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch JWKS")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read JWKS")
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	ks.keys = keys
+	return nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns RSA and EC signing keys of the set. Keys of other types or meant for
+// encryption are skipped.
+func parseJWKS(data []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWKS")
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var key any
+		var err error
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			key, err = k.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid JWKS key %q", k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no signing keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid modulus")
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid exponent")
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent is too large")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k jwk) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid x coordinate")
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid y coordinate")
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("value is empty")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -2,7 +2,16 @@ package banks
 
 import (
 	"context"
+	"financing-aggregator/internal/config"
 	"financing-aggregator/internal/dto"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrWebhooksNotSupported  = errors.New("bank does not support webhooks")
+	ErrInvalidSignature      = errors.New("invalid webhook signature")
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
 )
 
 type (
@@ -11,4 +20,20 @@ type (
 		SubmitApplication(ctx context.Context, data dto.ApplicationDTO) (dto.OfferDTO, error)
 		GetApplication(ctx context.Context, id string) (dto.OfferDTO, error)
 	}
+
+	// HealthChecker is implemented by banks that can report the state of their connection.
+	HealthChecker interface {
+		Health() string
+	}
+
+	// Factory creates a bank from its config, e.g. with settings of a merchant.
+	Factory func(name string, cfg config.BankConfig) (Bank, error)
+
+	// WebhookReceiver is implemented by banks that can push application updates.
+	WebhookReceiver interface {
+		// ParseWebhook verifies the payload signature and maps the payload to an offer.
+		ParseWebhook(signature string, body []byte) (dto.OfferDTO, error)
+		// PollingDisabled reports whether the bank relies on webhooks only.
+		PollingDisabled() bool
+	}
 )
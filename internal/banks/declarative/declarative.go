@@ -0,0 +1,234 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/banks/httpclient"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/models"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// offerStatuses are the offer statuses a bank status may be mapped to.
+var offerStatuses = []string{models.OfferStatusDraft, models.OfferStatusProcessed, models.OfferStatusDeclined}
+
+// Bank is a banks.Bank implementation driven entirely by config.BankConfig.
+type Bank struct {
+	name   string
+	cfg    config.BankConfig
+	client *httpclient.Client
+}
+
+func NewBank(name string, cfg config.BankConfig, logger *zap.Logger) (banks.Bank, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s config", name)
+	}
+
+	client, err := httpclient.New(name, cfg.HTTP, logger)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s config", name)
+	}
+
+	return &Bank{
+		name:   name,
+		cfg:    cfg,
+		client: client,
+	}, nil
+}
+
+func (b *Bank) Name() string {
+	return b.name
+}
+
+func (b *Bank) Health() string {
+	return b.client.State()
+}
+
+func (b *Bank) SubmitApplication(ctx context.Context, data dto.ApplicationDTO) (dto.OfferDTO, error) {
+	body := make(map[string]any, len(b.cfg.Request))
+	for _, f := range b.cfg.Request {
+		if err := setPath(body, f.Field, applicationFields[f.Source](data)); err != nil {
+			return dto.OfferDTO{}, err
+		}
+	}
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return dto.OfferDTO{}, err
+	}
+
+	offer, err := b.do(ctx, b.cfg.Submit, "", reqBody)
+	if err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.ExternalID == "" {
+		return dto.OfferDTO{}, fmt.Errorf("%s: response has no application id", b.name)
+	}
+	return offer, nil
+}
+
+func (b *Bank) GetApplication(ctx context.Context, id string) (dto.OfferDTO, error) {
+	return b.do(ctx, b.cfg.Status, id, nil)
+}
+
+// ParseWebhook verifies the hex-encoded HMAC-SHA256 signature of the payload, optionally
+// prefixed with "sha256=", and maps the payload with the configured response paths.
+func (b *Bank) ParseWebhook(signature string, body []byte) (dto.OfferDTO, error) {
+	if b.cfg.Webhook.Secret == "" {
+		return dto.OfferDTO{}, banks.ErrWebhooksNotSupported
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return dto.OfferDTO{}, banks.ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(b.cfg.Webhook.Secret))
+	mac.Write(body)
+	if !hmac.Equal(expected, mac.Sum(nil)) {
+		return dto.OfferDTO{}, banks.ErrInvalidSignature
+	}
+
+	var payload map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return dto.OfferDTO{}, errors.Wrap(banks.ErrInvalidWebhookPayload, err.Error())
+	}
+
+	offer, err := b.mapResponse(payload)
+	if err != nil {
+		return dto.OfferDTO{}, errors.Wrap(banks.ErrInvalidWebhookPayload, err.Error())
+	}
+	if offer.ExternalID == "" {
+		return dto.OfferDTO{}, errors.Wrap(banks.ErrInvalidWebhookPayload, "payload has no application id")
+	}
+	return offer, nil
+}
+
+func (b *Bank) PollingDisabled() bool {
+	return b.cfg.Webhook.Secret != "" && b.cfg.Webhook.DisablePolling
+}
+
+func (b *Bank) do(ctx context.Context, endpoint config.BankEndpoint, id string, reqBody []byte) (dto.OfferDTO, error) {
+	path := strings.ReplaceAll(endpoint.Path, "{id}", url.PathEscape(id))
+	resp, err := b.client.Do(ctx, endpoint.Method, b.cfg.BaseURL+path, reqBody)
+	if err != nil {
+		return dto.OfferDTO{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return dto.OfferDTO{}, fmt.Errorf("%s: unexpected status %d: %s", b.name, resp.StatusCode, string(body))
+	}
+
+	var response map[string]any
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&response); err != nil {
+		return dto.OfferDTO{}, err
+	}
+
+	offer, err := b.mapResponse(response)
+	if err != nil {
+		return dto.OfferDTO{}, errors.Wrapf(err, "%s: failed to map response", b.name)
+	}
+	return offer, nil
+}
+
+func (b *Bank) mapResponse(body map[string]any) (dto.OfferDTO, error) {
+	m := b.cfg.Response
+	offer := dto.OfferDTO{Bank: b.name}
+
+	var err error
+	if offer.ExternalID, err = getString(body, m.ID); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.Status, err = getString(body, m.Status); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.Status, err = b.mapStatus(offer.Status); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.MonthlyPaymentAmount, err = getAmount(body, m.MonthlyPaymentAmount); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.TotalRepaymentAmount, err = getAmount(body, m.TotalRepaymentAmount); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.NumberOfPayments, err = getInt(body, m.NumberOfPayments); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.AnnualPercentageRate, err = getFloat(body, m.AnnualPercentageRate); err != nil {
+		return dto.OfferDTO{}, err
+	}
+	if offer.FirstRepaymentDate, err = getString(body, m.FirstRepaymentDate); err != nil {
+		return dto.OfferDTO{}, err
+	}
+
+	return offer, nil
+}
+
+func (b *Bank) mapStatus(status string) (string, error) {
+	if len(b.cfg.Statuses) == 0 {
+		return status, nil
+	}
+
+	// viper lowercases map keys, so statuses are matched case-insensitively.
+	for from, to := range b.cfg.Statuses {
+		if strings.EqualFold(from, status) {
+			return to, nil
+		}
+	}
+	return "", fmt.Errorf("unknown status %q", status)
+}
+
+func validateConfig(cfg config.BankConfig) error {
+	if cfg.BaseURL == "" {
+		return errors.New("base url is required")
+	}
+
+	for name, endpoint := range map[string]config.BankEndpoint{"submit": cfg.Submit, "status": cfg.Status} {
+		if endpoint.Method == "" || endpoint.Path == "" {
+			return errors.Errorf("%s endpoint requires method and path", name)
+		}
+	}
+	if !strings.Contains(cfg.Status.Path, "{id}") {
+		return errors.New("status endpoint path must contain {id}")
+	}
+
+	for _, f := range cfg.Request {
+		if f.Field == "" {
+			return errors.New("request field name is required")
+		}
+		if _, ok := applicationFields[f.Source]; !ok {
+			return errors.Errorf("unknown request field source %q", f.Source)
+		}
+	}
+
+	if cfg.Response.ID == "" || cfg.Response.Status == "" {
+		return errors.New("response id and status paths are required")
+	}
+
+	for from, to := range cfg.Statuses {
+		if !slices.Contains(offerStatuses, to) {
+			return errors.Errorf("status %q is mapped to unknown offer status %q", from, to)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package declarative
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type declarativeBankTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(declarativeBankTestSuite))
+}
+
+func (s *declarativeBankTestSuite) Test_SubmitApplication() {
+	var actualBody map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPut, r.Method)
+		s.Equal("/loans", r.URL.Path)
+		s.Equal("application/json", r.Header.Get("Content-Type"))
+		s.NoError(json.NewDecoder(r.Body).Decode(&actualBody))
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"ref":"ext-1","state":"new"}}`))
+	}))
+	defer ts.Close()
+
+	bank := s.newBank(ts.URL)
+	actual, err := bank.SubmitApplication(context.Background(), getTestApplicationDTO())
+	s.NoError(err)
+	s.Equal(dto.OfferDTO{ExternalID: "ext-1", Status: "DRAFT", Bank: "testbank"}, actual)
+	s.Equal(map[string]any{
+		"applicant": map[string]any{"phone": "+37122334455", "email": "anakin@skywalker.com"},
+		"amount":    100.0,
+	}, actualBody)
+}
+
+func (s *declarativeBankTestSuite) Test_GetApplication() {
+	s.Run("offer mapped", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.Equal(http.MethodGet, r.Method)
+			s.Equal("/loans/ext-1/status", r.URL.Path)
+			_, _ = w.Write([]byte(`{"data":{"ref":"ext-1","state":"Approved","terms":{"monthly":50,"total":150,"count":3,"apr":10.5,"firstDate":"2025-01-01"}}}`))
+		}))
+		defer ts.Close()
+
+		actual, err := s.newBank(ts.URL).GetApplication(context.Background(), "ext-1")
+		s.NoError(err)
+		s.Equal(dto.OfferDTO{
+			ExternalID:           "ext-1",
+			Status:               "PROCESSED",
+			Bank:                 "testbank",
+			MonthlyPaymentAmount: money.MustParse("50"),
+			TotalRepaymentAmount: money.MustParse("150"),
+			NumberOfPayments:     3,
+			AnnualPercentageRate: 10.5,
+			FirstRepaymentDate:   "2025-01-01",
+		}, actual)
+	})
+
+	s.Run("error occurs because status is unknown", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data":{"ref":"ext-1","state":"weird"}}`))
+		}))
+		defer ts.Close()
+
+		_, err := s.newBank(ts.URL).GetApplication(context.Background(), "ext-1")
+		s.Error(err)
+		s.Contains(err.Error(), "unknown status")
+	})
+
+	s.Run("error occurs because bank responds with error", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		_, err := s.newBank(ts.URL).GetApplication(context.Background(), "ext-1")
+		s.Error(err)
+		s.Contains(err.Error(), "testbank: unexpected status 502")
+	})
+}
+
+func (s *declarativeBankTestSuite) Test_ParseWebhook() {
+	body := []byte(`{"data":{"ref":"ext-1","state":"approved","terms":{"monthly":50,"total":150,"count":3,"apr":10,"firstDate":"2025-01-01"}}}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	cfg := getTestBankConfig("http://localhost")
+	cfg.Webhook.Secret = "secret"
+	bank, err := NewBank("testbank", cfg, zap.NewNop())
+	s.Require().NoError(err)
+	receiver := bank.(banks.WebhookReceiver)
+
+	s.Run("payload mapped", func() {
+		for _, sig := range []string{signature, "sha256=" + signature} {
+			actual, err := receiver.ParseWebhook(sig, body)
+			s.NoError(err)
+			s.Equal("ext-1", actual.ExternalID)
+			s.Equal("PROCESSED", actual.Status)
+			s.Equal(3, actual.NumberOfPayments)
+		}
+	})
+
+	s.Run("error occurs because signature is invalid", func() {
+		_, err := receiver.ParseWebhook(signature, append(body, ' '))
+		s.ErrorIs(err, banks.ErrInvalidSignature)
+
+		_, err = receiver.ParseWebhook("not-hex", body)
+		s.ErrorIs(err, banks.ErrInvalidSignature)
+	})
+
+	s.Run("error occurs because payload has no id", func() {
+		payload := []byte(`{"data":{"state":"approved"}}`)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(payload)
+
+		_, err := receiver.ParseWebhook(hex.EncodeToString(mac.Sum(nil)), payload)
+		s.ErrorIs(err, banks.ErrInvalidWebhookPayload)
+	})
+
+	s.Run("error occurs because webhooks are not configured", func() {
+		_, err := s.newBank("http://localhost").ParseWebhook(signature, body)
+		s.ErrorIs(err, banks.ErrWebhooksNotSupported)
+	})
+}
+
+func (s *declarativeBankTestSuite) Test_NewBank() {
+	s.Run("error occurs because request source is unknown", func() {
+		cfg := getTestBankConfig("http://localhost")
+		cfg.Request = append(cfg.Request, config.BankRequestField{Field: "salary", Source: "salary"})
+
+		_, err := NewBank("testbank", cfg, zap.NewNop())
+		s.Error(err)
+		s.Contains(err.Error(), `unknown request field source "salary"`)
+	})
+
+	s.Run("error occurs because status path has no id", func() {
+		cfg := getTestBankConfig("http://localhost")
+		cfg.Status.Path = "/loans"
+
+		_, err := NewBank("testbank", cfg, zap.NewNop())
+		s.Error(err)
+		s.Contains(err.Error(), "{id}")
+	})
+
+	s.Run("error occurs because status is mapped to unknown offer status", func() {
+		cfg := getTestBankConfig("http://localhost")
+		cfg.Statuses["approved"] = "PROCESED"
+
+		_, err := NewBank("testbank", cfg, zap.NewNop())
+		s.Error(err)
+		s.Contains(err.Error(), `unknown offer status "PROCESED"`)
+	})
+}
+
+func (s *declarativeBankTestSuite) newBank(baseURL string) *Bank {
+	bank, err := NewBank("testbank", getTestBankConfig(baseURL), zap.NewNop())
+	s.Require().NoError(err)
+	return bank.(*Bank)
+}
+
+func getTestBankConfig(baseURL string) config.BankConfig {
+	return config.BankConfig{
+		BaseURL: baseURL,
+		Submit:  config.BankEndpoint{Method: http.MethodPut, Path: "/loans"},
+		Status:  config.BankEndpoint{Method: http.MethodGet, Path: "/loans/{id}/status"},
+		Request: []config.BankRequestField{
+			{Field: "applicant.phone", Source: "phone"},
+			{Field: "applicant.email", Source: "email"},
+			{Field: "amount", Source: "amount"},
+		},
+		Response: config.BankResponseMapping{
+			ID:                   "data.ref",
+			Status:               "data.state",
+			MonthlyPaymentAmount: "data.terms.monthly",
+			TotalRepaymentAmount: "data.terms.total",
+			NumberOfPayments:     "data.terms.count",
+			AnnualPercentageRate: "data.terms.apr",
+			FirstRepaymentDate:   "data.terms.firstDate",
+		},
+		Statuses: map[string]string{
+			"new":      "DRAFT",
+			"approved": "PROCESSED",
+		},
+	}
+}
+
+func getTestApplicationDTO() dto.ApplicationDTO {
+	return dto.ApplicationDTO{
+		Phone:  "+37122334455",
+		Email:  "anakin@skywalker.com",
+		Amount: money.MustParse("100"),
+	}
+}
@@ -0,0 +1,108 @@
+package declarative
+
+import (
+	"encoding/json"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"fmt"
+	"strings"
+)
+
+// applicationFields lists application values that can be sent to banks, keyed by the
+// name used in BankRequestField.Source.
+var applicationFields = map[string]func(dto.ApplicationDTO) any{
+	"phone":                    func(a dto.ApplicationDTO) any { return a.Phone },
+	"email":                    func(a dto.ApplicationDTO) any { return a.Email },
+	"amount":                   func(a dto.ApplicationDTO) any { return a.Amount },
+	"currency":                 func(a dto.ApplicationDTO) any { return a.Currency },
+	"monthlyIncome":            func(a dto.ApplicationDTO) any { return a.MonthlyIncome },
+	"monthlyExpenses":          func(a dto.ApplicationDTO) any { return a.MonthlyExpenses },
+	"monthlyCreditLiabilities": func(a dto.ApplicationDTO) any { return a.MonthlyCreditLiabilities },
+	"maritalStatus":            func(a dto.ApplicationDTO) any { return a.MaritalStatus },
+	"dependents":               func(a dto.ApplicationDTO) any { return a.Dependents },
+	"agreeToDataSharing":       func(a dto.ApplicationDTO) any { return a.AgreeToDataSharing },
+	"agreeToBeScored":          func(a dto.ApplicationDTO) any { return a.AgreeToBeScored },
+}
+
+// setPath puts value into body under a dot-separated path, creating nested objects on the way.
+func setPath(body map[string]any, path string, value any) error {
+	keys := strings.Split(path, ".")
+	current := body
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key]
+		if !ok {
+			next = make(map[string]any)
+			current[key] = next
+		}
+
+		nested, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("field %q conflicts with another request field", path)
+		}
+		current = nested
+	}
+
+	current[keys[len(keys)-1]] = value
+	return nil
+}
+
+// getPath returns the value under a dot-separated path, or nil if any part of the path is missing.
+func getPath(body map[string]any, path string) any {
+	if path == "" {
+		return nil
+	}
+
+	var current any = body
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = obj[key]
+	}
+	return current
+}
+
+func getString(body map[string]any, path string) (string, error) {
+	switch v := getPath(body, path).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("field %q is not a string", path)
+	}
+}
+
+func getFloat(body map[string]any, path string) (float64, error) {
+	switch v := getPath(body, path).(type) {
+	case nil:
+		return 0, nil
+	case json.Number:
+		return v.Float64()
+	default:
+		return 0, fmt.Errorf("field %q is not a number", path)
+	}
+}
+
+// getAmount reads an exact amount, rounding sub-cent values stated by the bank to cents.
+func getAmount(body map[string]any, path string) (money.Amount, error) {
+	switch v := getPath(body, path).(type) {
+	case nil:
+		return 0, nil
+	case json.Number:
+		return money.ParseRounded(v.String())
+	default:
+		return 0, fmt.Errorf("field %q is not a number", path)
+	}
+}
+
+func getInt(body map[string]any, path string) (int, error) {
+	f, err := getFloat(body, path)
+	if err != nil {
+		return 0, err
+	}
+	return int(f), nil
+}
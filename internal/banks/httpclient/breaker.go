@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker stops calls to a bank after a number of consecutive failures. Once
+// openTimeout passes, a single trial call is let through: its success closes the
+// circuit again, its failure keeps it open for another openTimeout.
+type circuitBreaker struct {
+	name             string
+	logger           *zap.Logger
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(name string, failureThreshold int, openTimeout time.Duration, logger *zap.Logger) *circuitBreaker {
+	return &circuitBreaker{
+		name:             name,
+		logger:           logger,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+		state:            StateClosed,
+	}
+}
+
+func (b *circuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) Allow() error {
+	if b.failureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.transition(StateHalfOpen)
+		b.trial = true
+		return nil
+	case StateHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+	if b.state != StateClosed {
+		b.transition(StateClosed)
+	}
+}
+
+func (b *circuitBreaker) Failure() {
+	if b.failureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.state == StateHalfOpen || (b.state == StateClosed && b.failures >= b.failureThreshold) {
+		b.openedAt = b.now()
+		b.transition(StateOpen)
+	}
+}
+
+// Abort releases a call that ended without telling anything about bank health,
+// e.g. because the caller cancelled it.
+func (b *circuitBreaker) Abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+func (b *circuitBreaker) transition(state string) {
+	b.logger.Warn("bank circuit breaker state changed",
+		zap.String("bank", b.name),
+		zap.String("from", b.state),
+		zap.String("to", state),
+		zap.Int("failures", b.failures),
+	)
+	b.state = state
+}
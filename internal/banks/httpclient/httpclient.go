@@ -0,0 +1,175 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"financing-aggregator/internal/config"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout        = 10 * time.Second
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// Client is an HTTP client shared by bank implementations. It limits every attempt
+// with a timeout, retries transient failures (network errors, 429 and 5xx responses)
+// of idempotent requests with jittered exponential backoff and guards the bank with a
+// circuit breaker.
+type Client struct {
+	name    string
+	cfg     config.BankHTTPConfig
+	logger  *zap.Logger
+	client  *http.Client
+	breaker *circuitBreaker
+}
+
+func New(name string, cfg config.BankHTTPConfig, logger *zap.Logger) (*Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.Retry.MaxAttempts <= 0 {
+		cfg.Retry.MaxAttempts = 1
+	}
+	if cfg.Retry.InitialBackoff <= 0 {
+		cfg.Retry.InitialBackoff = defaultInitialBackoff
+	}
+	if cfg.Retry.MaxBackoff <= 0 {
+		cfg.Retry.MaxBackoff = defaultMaxBackoff
+	}
+
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid tls config")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &Client{
+		name:   name,
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+		},
+		breaker: newCircuitBreaker(name, cfg.CircuitBreaker.FailureThreshold, cfg.CircuitBreaker.OpenTimeout, logger),
+	}, nil
+}
+
+// State returns the circuit breaker state of the bank.
+func (c *Client) State() string {
+	return c.breaker.State()
+}
+
+// Do sends a request with a JSON body, if one is given. The response of the last
+// attempt is returned even if its status is transient, so callers can report it.
+// Requests of other than idempotent methods are sent once, as the bank may have
+// processed an attempt that failed, e.g. timed out, and creating an application twice
+// runs a second credit check. Callers retry them.
+func (c *Client) Do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	maxAttempts := c.cfg.Retry.MaxAttempts
+	if !isIdempotent(method) {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := sleep(ctx, c.backoff(attempt-1)); err != nil {
+				return nil, lastErr
+			}
+		}
+
+		if err := c.breaker.Allow(); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+
+		resp, err := c.do(ctx, method, url, body)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			c.breaker.Abort()
+			return nil, err
+		case err != nil:
+			c.breaker.Failure()
+			lastErr = err
+		case isTransient(resp.StatusCode):
+			c.breaker.Failure()
+			if attempt == maxAttempts {
+				return resp, nil
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("%s: unexpected status %d", c.name, resp.StatusCode)
+		default:
+			c.breaker.Success()
+			return resp, nil
+		}
+
+		c.logger.Warn("bank request failed",
+			zap.String("bank", c.name),
+			zap.String("url", url),
+			zap.Int("attempt", attempt),
+			zap.Error(lastErr),
+		)
+	}
+
+	return nil, lastErr
+}
+
+func (c *Client) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range c.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.client.Do(req)
+}
+
+// backoff returns a random delay up to the exponentially growing, capped backoff ("full jitter").
+func (c *Client) backoff(retry int) time.Duration {
+	limit := c.cfg.Retry.MaxBackoff
+	if shift := retry - 1; shift < 16 {
+		limit = min(c.cfg.Retry.InitialBackoff<<shift, c.cfg.Retry.MaxBackoff)
+	}
+	return time.Duration(rand.Int64N(int64(limit) + 1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isTransient(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,169 @@
+package httpclient
+
+import (
+	"context"
+	"financing-aggregator/internal/config"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type httpClientTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(httpClientTestSuite))
+}
+
+func (s *httpClientTestSuite) Test_Do() {
+	s.Run("transient errors retried", func() {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			s.Equal(`{"a":1}`, string(body))
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer ts.Close()
+
+		resp, err := s.newClient(config.BankHTTPConfig{}).Do(context.Background(), http.MethodPut, ts.URL, []byte(`{"a":1}`))
+		s.NoError(err)
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		s.Equal(int32(3), calls.Load())
+	})
+
+	s.Run("non-idempotent requests not retried", func() {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		resp, err := s.newClient(config.BankHTTPConfig{}).Do(context.Background(), http.MethodPost, ts.URL, []byte(`{}`))
+		s.NoError(err)
+		s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+		s.Equal(int32(1), calls.Load())
+	})
+
+	s.Run("client errors not retried", func() {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer ts.Close()
+
+		resp, err := s.newClient(config.BankHTTPConfig{}).Do(context.Background(), http.MethodGet, ts.URL, nil)
+		s.NoError(err)
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+		s.Equal(int32(1), calls.Load())
+	})
+
+	s.Run("last transient response returned after all attempts", func() {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		resp, err := s.newClient(config.BankHTTPConfig{}).Do(context.Background(), http.MethodGet, ts.URL, nil)
+		s.NoError(err)
+		s.Equal(http.StatusInternalServerError, resp.StatusCode)
+		s.Equal(int32(3), calls.Load())
+	})
+
+	s.Run("configured headers sent", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.Equal("secret", r.Header.Get("X-Api-Key"))
+			s.Equal("application/json", r.Header.Get("Content-Type"))
+		}))
+		defer ts.Close()
+
+		cfg := config.BankHTTPConfig{Headers: map[string]string{"x-api-key": "secret"}}
+		resp, err := s.newClient(cfg).Do(context.Background(), http.MethodPost, ts.URL, []byte(`{}`))
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("error occurs because attempt times out", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer ts.Close()
+
+		_, err := s.newClient(config.BankHTTPConfig{Timeout: 20 * time.Millisecond}).Do(context.Background(), http.MethodGet, ts.URL, nil)
+		s.Error(err)
+		s.Contains(err.Error(), "Client.Timeout exceeded")
+	})
+}
+
+func (s *httpClientTestSuite) Test_CircuitBreaker() {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	client := s.newClient(config.BankHTTPConfig{
+		Retry:          config.RetryConfig{MaxAttempts: 1},
+		CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute},
+	})
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	s.Run("circuit opens after consecutive failures", func() {
+		for range 2 {
+			resp, err := client.Do(context.Background(), http.MethodGet, ts.URL, nil)
+			s.NoError(err)
+			s.Equal(http.StatusBadGateway, resp.StatusCode)
+		}
+		s.Equal(StateOpen, client.State())
+
+		_, err := client.Do(context.Background(), http.MethodGet, ts.URL, nil)
+		s.ErrorIs(err, ErrCircuitOpen)
+		s.Equal(int32(2), calls.Load())
+	})
+
+	s.Run("failed trial call keeps circuit open", func() {
+		now = now.Add(time.Minute)
+
+		_, err := client.Do(context.Background(), http.MethodGet, ts.URL, nil)
+		s.NoError(err)
+		s.Equal(StateOpen, client.State())
+		s.Equal(int32(3), calls.Load())
+	})
+
+	s.Run("successful trial call closes circuit", func() {
+		now = now.Add(time.Minute)
+		healthy.Store(true)
+
+		resp, err := client.Do(context.Background(), http.MethodGet, ts.URL, nil)
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal(StateClosed, client.State())
+	})
+}
+
+func (s *httpClientTestSuite) newClient(cfg config.BankHTTPConfig) *Client {
+	if cfg.Retry.MaxAttempts == 0 {
+		cfg.Retry = config.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	}
+	client, err := New("testbank", cfg, zap.NewNop())
+	s.Require().NoError(err)
+	return client
+}
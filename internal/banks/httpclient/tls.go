@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"financing-aggregator/internal/config"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.MinVersion != "" {
+		version, ok := tlsVersions[cfg.MinVersion]
+		if !ok {
+			return nil, errors.Errorf("unsupported tls version %q", cfg.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read ca file")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(cfg.PinnedSHA256) > 0 {
+		pins := make(map[string]struct{}, len(cfg.PinnedSHA256))
+		for _, pin := range cfg.PinnedSHA256 {
+			pins[pin] = struct{}{}
+		}
+		tlsConfig.VerifyConnection = verifyPins(pins)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyPins accepts a connection only if one of the certificates presented by the bank
+// has a pinned public key. It runs after regular chain verification.
+func verifyPins(pins map[string]struct{}) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		for _, cert := range cs.PeerCertificates {
+			if _, ok := pins[publicKeyPin(cert)]; ok {
+				return nil
+			}
+		}
+		return errors.New("bank certificate does not match any pinned public key")
+	}
+}
+
+// publicKeyPin returns the base64-encoded SHA-256 hash of the certificate public key.
+func publicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
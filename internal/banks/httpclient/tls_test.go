@@ -0,0 +1,185 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"financing-aggregator/internal/config"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type tlsTestSuite struct {
+	suite.Suite
+	dir string
+
+	ca         testCert
+	server     testCert
+	client     testCert
+	serverPool *x509.CertPool
+}
+
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+func TestTLSSuite(t *testing.T) {
+	suite.Run(t, new(tlsTestSuite))
+}
+
+func (s *tlsTestSuite) SetupSuite() {
+	s.dir = s.T().TempDir()
+	s.ca = s.newCert("ca", nil)
+	s.server = s.newCert("server", &s.ca)
+	s.client = s.newCert("client", &s.ca)
+
+	s.serverPool = x509.NewCertPool()
+	s.serverPool.AddCert(s.ca.cert)
+}
+
+func (s *tlsTestSuite) Test_ServerVerification() {
+	ts := s.newServer(tls.NoClientCert, tls.VersionTLS13)
+	defer ts.Close()
+
+	s.Run("error occurs because server certificate is not trusted", func() {
+		s.Error(s.call(ts.URL, config.TLSConfig{}))
+	})
+
+	s.Run("server trusted by configured ca", func() {
+		s.NoError(s.call(ts.URL, config.TLSConfig{CAFile: s.ca.certFile}))
+	})
+
+	s.Run("server certificate matches pinned key", func() {
+		s.NoError(s.call(ts.URL, config.TLSConfig{
+			CAFile:       s.ca.certFile,
+			PinnedSHA256: []string{"unknown", publicKeyPin(s.server.cert)},
+		}))
+	})
+
+	s.Run("error occurs because server certificate does not match pinned key", func() {
+		err := s.call(ts.URL, config.TLSConfig{
+			CAFile:       s.ca.certFile,
+			PinnedSHA256: []string{publicKeyPin(s.client.cert)},
+		})
+		s.Error(err)
+		s.Contains(err.Error(), "pinned public key")
+	})
+}
+
+func (s *tlsTestSuite) Test_MutualTLS() {
+	ts := s.newServer(tls.RequireAndVerifyClientCert, tls.VersionTLS13)
+	defer ts.Close()
+
+	s.Run("error occurs because client certificate is missing", func() {
+		s.Error(s.call(ts.URL, config.TLSConfig{CAFile: s.ca.certFile}))
+	})
+
+	s.Run("client certificate accepted", func() {
+		s.NoError(s.call(ts.URL, config.TLSConfig{
+			CAFile:   s.ca.certFile,
+			CertFile: s.client.certFile,
+			KeyFile:  s.client.keyFile,
+		}))
+	})
+}
+
+func (s *tlsTestSuite) Test_MinVersion() {
+	ts := s.newServer(tls.NoClientCert, tls.VersionTLS12)
+	defer ts.Close()
+
+	s.Run("tls 1.2 server accepted by default", func() {
+		s.NoError(s.call(ts.URL, config.TLSConfig{CAFile: s.ca.certFile}))
+	})
+
+	s.Run("error occurs because server does not support minimal version", func() {
+		s.Error(s.call(ts.URL, config.TLSConfig{CAFile: s.ca.certFile, MinVersion: "1.3"}))
+	})
+
+	s.Run("error occurs because version is unknown", func() {
+		_, err := newTLSConfig(config.TLSConfig{MinVersion: "2.0"})
+		s.Error(err)
+	})
+}
+
+func (s *tlsTestSuite) newServer(clientAuth tls.ClientAuthType, maxVersion uint16) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{s.server.cert.Raw}, PrivateKey: s.server.key}},
+		ClientAuth:   clientAuth,
+		ClientCAs:    s.serverPool,
+		MaxVersion:   maxVersion,
+	}
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	return ts
+}
+
+func (s *tlsTestSuite) call(url string, cfg config.TLSConfig) error {
+	client, err := New("testbank", config.BankHTTPConfig{TLS: cfg}, zap.NewNop())
+	s.Require().NoError(err)
+
+	resp, err := client.Do(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *tlsTestSuite) newCert(name string, parent *testCert) testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	s.Require().NoError(err)
+	cert, err := x509.ParseCertificate(der)
+	s.Require().NoError(err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	s.Require().NoError(err)
+
+	c := testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(s.dir, name+".crt"),
+		keyFile:  filepath.Join(s.dir, name+".key"),
+	}
+	s.Require().NoError(os.WriteFile(c.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	s.Require().NoError(os.WriteFile(c.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return c
+}
@@ -0,0 +1,202 @@
+package banksim
+
+import (
+	"financing-aggregator/internal/config"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	statusDraft     = "DRAFT"
+	statusProcessed = "PROCESSED"
+
+	dateFormat = "2006-01-02"
+)
+
+var paymentTerms = []int{6, 12, 24, 36, 48, 60}
+
+type application struct {
+	id          string
+	submittedAt time.Time
+	offer       *Offer
+}
+
+// Simulator imitates FastBank and SolidBank application APIs. Offers stay in DRAFT
+// until the configured processing delay passes, after which they are either
+// processed with generated terms or declined, in which case no offer is returned.
+type Simulator struct {
+	cfg    config.BankSimConfig
+	logger *zap.Logger
+
+	mu           sync.Mutex
+	rnd          *rand.Rand
+	applications map[string]*application
+}
+
+func New(cfg config.BankSimConfig, logger *zap.Logger) *Simulator {
+	return &Simulator{
+		cfg:          cfg,
+		logger:       logger,
+		rnd:          rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+		applications: make(map[string]*application),
+	}
+}
+
+func (s *Simulator) Handler() http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	r.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	api := r.Group("/api", s.latencyMiddleware(), s.errorMiddleware())
+	api.POST("/FastBank/applications", s.submitFastBankApplication)
+	api.GET("/FastBank/applications/:id", s.getApplication)
+	api.POST("/SolidBank/applications", s.submitSolidBankApplication)
+	api.GET("/SolidBank/applications/:id", s.getApplication)
+
+	return r
+}
+
+func (s *Simulator) submitFastBankApplication(c *gin.Context) {
+	var req FastBankApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, s.createApplication(req.Amount))
+}
+
+func (s *Simulator) submitSolidBankApplication(c *gin.Context) {
+	var req SolidBankApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, s.createApplication(req.Amount))
+}
+
+func (s *Simulator) getApplication(c *gin.Context) {
+	s.mu.Lock()
+	app, ok := s.applications[c.Param("id")]
+	s.mu.Unlock()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.toResponse(app))
+}
+
+func (s *Simulator) createApplication(amount float64) ApplicationResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	app := &application{
+		id:          uuid.NewString(),
+		submittedAt: now,
+	}
+	if s.rnd.Float64() >= s.cfg.DeclineRate {
+		app.offer = s.generateOffer(amount, now)
+	}
+	s.applications[app.id] = app
+
+	s.logger.Debug("application received", zap.String("id", app.id), zap.Bool("declined", app.offer == nil))
+
+	return ApplicationResponse{
+		ID:     app.id,
+		Status: statusDraft,
+	}
+}
+
+func (s *Simulator) toResponse(app *application) ApplicationResponse {
+	if time.Since(app.submittedAt) < s.cfg.ProcessingDelay {
+		return ApplicationResponse{
+			ID:     app.id,
+			Status: statusDraft,
+		}
+	}
+
+	return ApplicationResponse{
+		ID:     app.id,
+		Status: statusProcessed,
+		Offer:  app.offer,
+	}
+}
+
+// generateOffer builds annuity terms for the requested amount with a random term and
+// an APR within the configured range.
+func (s *Simulator) generateOffer(amount float64, submittedAt time.Time) *Offer {
+	numberOfPayments := paymentTerms[s.rnd.IntN(len(paymentTerms))]
+	apr := round(s.cfg.MinAPR+s.rnd.Float64()*(s.cfg.MaxAPR-s.cfg.MinAPR), 3)
+
+	// APR is the effective annual rate, so the monthly rate compounds to it over a year.
+	monthlyPayment := amount / float64(numberOfPayments)
+	if rate := math.Pow(1+apr/100, 1.0/12) - 1; rate > 0 {
+		monthlyPayment = amount * rate / (1 - math.Pow(1+rate, -float64(numberOfPayments)))
+	}
+	monthlyPayment = round(monthlyPayment, 2)
+
+	return &Offer{
+		MonthlyPaymentAmount: monthlyPayment,
+		TotalRepaymentAmount: round(monthlyPayment*float64(numberOfPayments), 2),
+		NumberOfPayments:     numberOfPayments,
+		AnnualPercentageRate: apr,
+		FirstRepaymentDate:   submittedAt.AddDate(0, 1, 0).Format(dateFormat),
+	}
+}
+
+func (s *Simulator) latencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if latency := s.latency(); latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func (s *Simulator) errorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.mu.Lock()
+		fail := s.rnd.Float64() < s.cfg.ErrorRate
+		s.mu.Unlock()
+
+		if fail {
+			s.logger.Debug("injecting error", zap.String("path", c.Request.URL.Path))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "simulated failure"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func (s *Simulator) latency() time.Duration {
+	if s.cfg.MaxLatency <= s.cfg.MinLatency {
+		return s.cfg.MinLatency
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.MinLatency + time.Duration(s.rnd.Int64N(int64(s.cfg.MaxLatency-s.cfg.MinLatency)))
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,164 @@
+package banksim
+
+import (
+	"context"
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/banks/declarative"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"financing-aggregator/internal/verification"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type bankSimTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(bankSimTestSuite))
+}
+
+func (s *bankSimTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *bankSimTestSuite) Test_ProcessedOffer() {
+	ts := s.newServer(config.BankSimConfig{MinAPR: 10, MaxAPR: 10})
+	defer ts.Close()
+
+	for _, bank := range s.newBanks(ts.URL) {
+		s.Run(bank.Name(), func() {
+			submitted, err := bank.SubmitApplication(context.Background(), getTestApplicationDTO())
+			s.NoError(err)
+			s.Equal(statusDraft, submitted.Status)
+			s.NotEmpty(submitted.ExternalID)
+
+			processed, err := bank.GetApplication(context.Background(), submitted.ExternalID)
+			s.NoError(err)
+			s.Equal(statusProcessed, processed.Status)
+			s.Equal(10.0, processed.AnnualPercentageRate)
+			s.Positive(processed.NumberOfPayments)
+			s.Equal(processed.MonthlyPaymentAmount.Mul(float64(processed.NumberOfPayments)), processed.TotalRepaymentAmount)
+			s.Greater(processed.TotalRepaymentAmount, money.MustParse("1000"))
+			s.InDelta(10.0, verification.EffectiveAPR(money.MustParse("1000"), processed.MonthlyPaymentAmount, processed.NumberOfPayments), 0.1)
+		})
+	}
+}
+
+func (s *bankSimTestSuite) Test_DraftUntilProcessingDelay() {
+	ts := s.newServer(config.BankSimConfig{ProcessingDelay: time.Hour})
+	defer ts.Close()
+
+	for _, bank := range s.newBanks(ts.URL) {
+		s.Run(bank.Name(), func() {
+			submitted, err := bank.SubmitApplication(context.Background(), getTestApplicationDTO())
+			s.NoError(err)
+
+			actual, err := bank.GetApplication(context.Background(), submitted.ExternalID)
+			s.NoError(err)
+			s.Equal(statusDraft, actual.Status)
+			s.Zero(actual.NumberOfPayments)
+		})
+	}
+}
+
+func (s *bankSimTestSuite) Test_DeclinedOffer() {
+	ts := s.newServer(config.BankSimConfig{DeclineRate: 1})
+	defer ts.Close()
+
+	for _, bank := range s.newBanks(ts.URL) {
+		s.Run(bank.Name(), func() {
+			submitted, err := bank.SubmitApplication(context.Background(), getTestApplicationDTO())
+			s.NoError(err)
+
+			actual, err := bank.GetApplication(context.Background(), submitted.ExternalID)
+			s.NoError(err)
+			s.Equal(statusProcessed, actual.Status)
+			s.Zero(actual.NumberOfPayments)
+		})
+	}
+}
+
+func (s *bankSimTestSuite) Test_InjectedErrors() {
+	ts := s.newServer(config.BankSimConfig{ErrorRate: 1})
+	defer ts.Close()
+
+	for _, bank := range s.newBanks(ts.URL) {
+		s.Run(bank.Name(), func() {
+			_, err := bank.SubmitApplication(context.Background(), getTestApplicationDTO())
+			s.Error(err)
+			s.Contains(err.Error(), "unexpected status 500")
+		})
+	}
+}
+
+func (s *bankSimTestSuite) Test_UnknownApplication() {
+	ts := s.newServer(config.BankSimConfig{})
+	defer ts.Close()
+
+	for _, bank := range s.newBanks(ts.URL) {
+		s.Run(bank.Name(), func() {
+			_, err := bank.GetApplication(context.Background(), "unknown")
+			s.Error(err)
+			s.Contains(err.Error(), "unexpected status 404")
+		})
+	}
+}
+
+func (s *bankSimTestSuite) newServer(cfg config.BankSimConfig) *httptest.Server {
+	return httptest.NewServer(New(cfg, zap.NewNop()).Handler())
+}
+
+func (s *bankSimTestSuite) newBanks(url string) []banks.Bank {
+	fastBank, err := declarative.NewBank("fastbank", getTestBankConfig(url+"/api/FastBank", []config.BankRequestField{
+		{Field: "phoneNumber", Source: "phone"},
+		{Field: "email", Source: "email"},
+		{Field: "monthlyIncomeAmount", Source: "monthlyIncome"},
+		{Field: "amount", Source: "amount"},
+	}), zap.NewNop())
+	s.Require().NoError(err)
+
+	solidBank, err := declarative.NewBank("solidbank", getTestBankConfig(url+"/api/SolidBank", []config.BankRequestField{
+		{Field: "phone", Source: "phone"},
+		{Field: "email", Source: "email"},
+		{Field: "monthlyIncome", Source: "monthlyIncome"},
+		{Field: "amount", Source: "amount"},
+	}), zap.NewNop())
+	s.Require().NoError(err)
+
+	return []banks.Bank{fastBank, solidBank}
+}
+
+func getTestBankConfig(baseURL string, request []config.BankRequestField) config.BankConfig {
+	return config.BankConfig{
+		BaseURL: baseURL,
+		Submit:  config.BankEndpoint{Method: "POST", Path: "/applications"},
+		Status:  config.BankEndpoint{Method: "GET", Path: "/applications/{id}"},
+		Request: request,
+		Response: config.BankResponseMapping{
+			ID:                   "id",
+			Status:               "status",
+			MonthlyPaymentAmount: "offer.monthlyPaymentAmount",
+			TotalRepaymentAmount: "offer.totalRepaymentAmount",
+			NumberOfPayments:     "offer.numberOfPayments",
+			AnnualPercentageRate: "offer.annualPercentageRate",
+			FirstRepaymentDate:   "offer.firstRepaymentDate",
+		},
+	}
+}
+
+func getTestApplicationDTO() dto.ApplicationDTO {
+	return dto.ApplicationDTO{
+		Phone:           "+37122334455",
+		Email:           "anakin@skywalker.com",
+		Amount:          money.MustParse("1000"),
+		MonthlyIncome:   money.MustParse("1000"),
+		MonthlyExpenses: money.MustParse("100"),
+	}
+}
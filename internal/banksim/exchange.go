@@ -0,0 +1,37 @@
+package banksim
+
+type (
+	FastBankApplicationRequest struct {
+		PhoneNumber              string  `json:"phoneNumber" binding:"required"`
+		Email                    string  `json:"email" binding:"required"`
+		MonthlyIncomeAmount      float64 `json:"monthlyIncomeAmount"`
+		MonthlyCreditLiabilities float64 `json:"monthlyCreditLiabilities"`
+		Dependents               int     `json:"dependents"`
+		AgreeToDataSharing       bool    `json:"agreeToDataSharing"`
+		Amount                   float64 `json:"amount" binding:"required,gt=0"`
+	}
+
+	SolidBankApplicationRequest struct {
+		Phone           string  `json:"phone" binding:"required"`
+		Email           string  `json:"email" binding:"required"`
+		MonthlyIncome   float64 `json:"monthlyIncome"`
+		MonthlyExpenses float64 `json:"monthlyExpenses"`
+		MaritalStatus   string  `json:"maritalStatus"`
+		AgreeToBeScored bool    `json:"agreeToBeScored"`
+		Amount          float64 `json:"amount" binding:"required,gt=0"`
+	}
+
+	ApplicationResponse struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Offer  *Offer `json:"offer"`
+	}
+
+	Offer struct {
+		MonthlyPaymentAmount float64 `json:"monthlyPaymentAmount"`
+		TotalRepaymentAmount float64 `json:"totalRepaymentAmount"`
+		NumberOfPayments     int     `json:"numberOfPayments"`
+		AnnualPercentageRate float64 `json:"annualPercentageRate"`
+		FirstRepaymentDate   string  `json:"firstRepaymentDate"`
+	}
+)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	"strings"
+	"time"
 )
 
 func ReadConfig() *Config {
@@ -29,11 +30,21 @@ func ReadConfig() *Config {
 
 type (
 	Config struct {
-		Env      string
-		Port     int
-		DB       DBConfig
-		CronTabs CronTabs
-		Banks    Banks
+		Env               string
+		Port              int
+		DB                DBConfig
+		Auth              AuthConfig
+		CronTabs          CronTabs
+		Submissions       SubmissionsConfig
+		Queue             QueueConfig
+		Ranking           RankingConfig
+		OfferVerification OfferVerificationConfig
+		Markets           MarketsConfig
+		Idempotency       IdempotencyConfig
+		Duplicates        DuplicatesConfig
+		Merchants         MerchantsConfig
+		Banks             Banks
+		BankSim           BankSimConfig
 	}
 
 	DBConfig struct {
@@ -44,12 +55,223 @@ type (
 		Name     string
 	}
 
+	// AuthConfig controls verification of bearer tokens. HS256 tokens are verified with
+	// HS256Secret, RS256 and ES256 tokens with keys from JWKSFile or JWKSURL.
+	AuthConfig struct {
+		// Issuer and Audience are required in the iss and aud claims if set.
+		Issuer      string
+		Audience    string
+		HS256Secret string
+		JWKSFile    string
+		JWKSURL     string
+		// JWKSRefreshInterval is the minimum time between fetches of JWKSURL when a token is
+		// signed with an unknown key, 5 minutes by default.
+		JWKSRefreshInterval time.Duration
+		// Leeway is the accepted clock skew in expiry and not-before checks.
+		Leeway time.Duration
+		// TokenTTL is the lifetime of HS256 tokens issued to users logging in, 1 hour by default.
+		TokenTTL time.Duration
+	}
+
 	CronTabs struct {
-		CheckOffersCronTab string
+		CheckOffersCronTab        string
+		ProcessSubmissionsCronTab string
+		ExpireOffersCronTab       string
+		PurgeQuotaUsageCronTab    string
+	}
+
+	// QueueConfig controls how replicas share submissions and status polls. Every worker
+	// claims rows for Lease, after which they can be picked up by another worker.
+	QueueConfig struct {
+		// WorkerID identifies the replica, host name and process ID are used if empty.
+		WorkerID      string
+		Lease         time.Duration
+		PollBatchSize int
+		// PollWorkers is the number of offers polled concurrently.
+		PollWorkers int
+		// PollTimeout limits a single polling run, offers not polled in time are left for the next run.
+		PollTimeout time.Duration
+		// SubmitTimeout limits a single submission run, including requests to banks, and must be
+		// shorter than Lease. Submissions not sent in time are released for the next run.
+		SubmitTimeout time.Duration
+	}
+
+	// RankingConfig lists criteria offers are ranked by, the first criterion is the most
+	// important one. Supported criteria are apr, totalRepayment and monthlyPayment.
+	RankingConfig struct {
+		Criteria []string
+	}
+
+	// OfferVerificationConfig holds tolerances of stated offer figures. Offers diverging beyond
+	// them are flagged, and quarantined, i.e. hidden from clients, if Quarantine is set.
+	OfferVerificationConfig struct {
+		// APRTolerance is the accepted APR difference in percentage points.
+		APRTolerance            float64
+		TotalRepaymentTolerance float64
+		Quarantine              bool
+	}
+
+	// MarketsConfig holds validation profiles of markets applications are submitted in, keyed
+	// by market code. The market is selected by the X-Market header, Default is used without it.
+	MarketsConfig struct {
+		Default  string
+		Profiles map[string]MarketProfile
+	}
+
+	// MarketProfile describes application fields accepted in a market.
+	MarketProfile struct {
+		PhonePrefix string
+		// PhoneLengths lists accepted lengths of phone numbers including the prefix, any if empty.
+		PhoneLengths []int
+		// RequiredFields lists JSON names of application fields that must not be empty.
+		RequiredFields []string
+		// MaritalStatuses lists accepted marital statuses, all statuses if empty.
+		MaritalStatuses []string
+	}
+
+	// IdempotencyConfig controls how long responses to requests with an Idempotency-Key
+	// header are replayed.
+	IdempotencyConfig struct {
+		// TTL is the time after which a key can be used for another request.
+		TTL time.Duration
+		// LockTimeout is the time after which a key of a request that never completed, e.g.
+		// because the replica crashed, can be used again.
+		LockTimeout time.Duration
+	}
+
+	// DuplicatesConfig controls detection of applications submitted again with the same email
+	// or phone and the same financial details, e.g. by double-clicking submit.
+	DuplicatesConfig struct {
+		// Window is the time within which a repeated application is a duplicate, 0 disables detection.
+		Window time.Duration
+		// Policy is "return" (default) to respond with the existing application or "reject" to fail with 409.
+		Policy string
+	}
+
+	// MerchantsConfig holds windows of API key quotas. A key may send RequestQuota requests
+	// per RequestQuotaWindow and submit ApplicationQuota applications per ApplicationQuotaWindow.
+	MerchantsConfig struct {
+		RequestQuotaWindow     time.Duration
+		ApplicationQuotaWindow time.Duration
+	}
+
+	// SubmissionsConfig controls delivery of applications to banks.
+	SubmissionsConfig struct {
+		// BatchSize is the maximum number of submissions processed per run.
+		BatchSize int
+		// MaxAttempts is the number of attempts after which a submission is marked as failed.
+		MaxAttempts    int
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+	}
+
+	Banks map[string]BankConfig
+
+	// BankConfig describes how a bank API is called and how its payloads map to
+	// application and offer fields, so that a new bank can be added without code changes.
+	BankConfig struct {
+		BaseURL string
+		// Currencies lists ISO 4217 codes of application amounts the bank accepts, EUR only if empty.
+		Currencies []string
+		Submit     BankEndpoint
+		Status     BankEndpoint
+		Request    []BankRequestField
+		Response   BankResponseMapping
+		// Statuses maps bank statuses to offer statuses. Keys are matched case-insensitively.
+		Statuses map[string]string
+		HTTP     BankHTTPConfig
+		Webhook  BankWebhookConfig
+		Polling  BankPollingConfig
+	}
+
+	// BankPollingConfig controls how often DRAFT offers of a bank are polled. The interval
+	// starts at InitialInterval and grows by Multiplier after every poll up to MaxInterval.
+	BankPollingConfig struct {
+		InitialInterval time.Duration
+		Multiplier      float64
+		MaxInterval     time.Duration
+		// MaxWait is the time after submission when a DRAFT offer times out, 0 disables it.
+		MaxWait time.Duration
+		// MaxConcurrency limits concurrent status requests to the bank, 0 means no limit.
+		MaxConcurrency int
+	}
+
+	// BankWebhookConfig enables status callbacks from a bank. Callback payloads are
+	// mapped with the same response paths as status responses.
+	BankWebhookConfig struct {
+		// Secret is the HMAC-SHA256 key callbacks are signed with, webhooks are disabled if empty.
+		Secret string
+		// DisablePolling stops polling DRAFT offers of the bank, relying on callbacks only.
+		DisablePolling bool
+	}
+
+	BankHTTPConfig struct {
+		// Timeout limits a single request attempt.
+		Timeout time.Duration
+		// Headers are sent with every request, e.g. credentials of the bank API.
+		Headers        map[string]string
+		Retry          RetryConfig
+		CircuitBreaker CircuitBreakerConfig
+		TLS            TLSConfig
+	}
+
+	TLSConfig struct {
+		// CAFile is a PEM bundle used instead of system roots to verify the bank certificate.
+		CAFile string
+		// CertFile and KeyFile hold the client certificate presented for mutual TLS.
+		CertFile string
+		KeyFile  string
+		// MinVersion is the lowest accepted TLS version, "1.2" by default.
+		MinVersion string
+		// PinnedSHA256 lists base64-encoded SHA-256 hashes of accepted certificate public keys.
+		PinnedSHA256       []string
+		InsecureSkipVerify bool
+	}
+
+	RetryConfig struct {
+		MaxAttempts    int
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+	}
+
+	CircuitBreakerConfig struct {
+		// FailureThreshold is the number of consecutive failures that opens the circuit, 0 disables it.
+		FailureThreshold int
+		OpenTimeout      time.Duration
+	}
+
+	BankEndpoint struct {
+		Method string
+		// Path is appended to BaseURL, {id} is replaced with the bank application ID.
+		Path string
+	}
+
+	BankRequestField struct {
+		// Field is a dot-separated path of the field in the bank request body.
+		Field string
+		// Source is the name of the application field the value is taken from.
+		Source string
+	}
+
+	// BankResponseMapping holds dot-separated paths of offer fields in bank responses.
+	BankResponseMapping struct {
+		ID                   string
+		Status               string
+		MonthlyPaymentAmount string
+		TotalRepaymentAmount string
+		NumberOfPayments     string
+		AnnualPercentageRate string
+		FirstRepaymentDate   string
 	}
 
-	Banks struct {
-		FastBankURL  string
-		SolidBankURL string
+	BankSimConfig struct {
+		Port            int
+		MinLatency      time.Duration
+		MaxLatency      time.Duration
+		ProcessingDelay time.Duration
+		DeclineRate     float64
+		ErrorRate       float64
+		MinAPR          float64
+		MaxAPR          float64
 	}
 )
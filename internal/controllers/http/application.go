@@ -1,55 +1,132 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/exchange"
 	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/ranking"
 	"financing-aggregator/internal/services"
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"net/http"
+	"time"
 )
 
-var validate = validator.New(validator.WithRequiredStructEnabled())
-
 type ApplicationHandler struct {
-	svc services.ApplicationService
+	svc       services.ApplicationService
+	users     services.UserService
+	merchants services.MerchantService
+	markets   *MarketValidator
 }
 
-func NewApplicationHandler(svc services.ApplicationService) *ApplicationHandler {
+func NewApplicationHandler(
+	svc services.ApplicationService,
+	users services.UserService,
+	merchants services.MerchantService,
+	markets *MarketValidator,
+) *ApplicationHandler {
 	return &ApplicationHandler{
-		svc: svc,
+		svc:       svc,
+		users:     users,
+		merchants: merchants,
+		markets:   markets,
 	}
 }
 
 // SubmitApplication
 //
 // @Summary		Submit a new financing application
-// @Description Accepts a JSON body with application details, validates input, and creates a new application.
+// @Description Accepts a JSON body with application details, validates input by the rules of the
+// @Description market selected by the X-Market header, and creates a new application. Applications
+// @Description of logged-in users are linked to them, and fields left out are taken from their profile.
+// @Description Applications submitted with a merchant API key are recorded for the merchant, sent to
+// @Description the banks of the merchant and, once created, count against the application quota of the key.
+// @Description Other tokens, except admin ones, cannot submit applications as they could not read them.
 // @Security 	BearerAuth
 // @Tags		applications
 // @Accept		json
 // @Produce		json
+// @Param		X-Market header string false "Market code, the configured default market if empty" Enums(LV, LT, EE)
+// @Param		Idempotency-Key header string false "Unique key of the request, retries with the same key and body return the original response"
 // @Param		application body exchange.ApplicationRequest true "Application request"
 // @Success		200 {object} exchange.ApplicationResponse
 // @Failure		400 {object} exchange.ErrorResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		409 {object} exchange.ErrorResponse
+// @Failure		422 {object} exchange.ErrorResponse
+// @Failure		429 {object} exchange.ErrorResponse
 // @Failure		500 {object} exchange.ErrorResponse
 // @Router 		/applications [post]
 func (h *ApplicationHandler) SubmitApplication(c *gin.Context) {
 	var req exchange.ApplicationRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
 		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
 		return
 	}
 
-	if err := validate.Struct(req); err != nil {
+	principal, _ := auth.FromContext(c)
+	userID, isUser := principal.UserID()
+	merchantID, isMerchant := principal.MerchantID()
+	if !isUser && !isMerchant && !principal.HasScope(auth.ScopeAdmin) {
+		c.JSON(http.StatusForbidden, exchange.NewErrorResponse("applications can only be submitted by users, merchants and admins"))
+		return
+	}
+
+	if isUser {
+		user, err := h.users.GetUser(c.Request.Context(), userID)
+		if err != nil {
+			userError(c, err)
+			return
+		}
+		// The body was bound above, so it is a JSON object.
+		var fields map[string]json.RawMessage
+		_ = c.ShouldBindBodyWith(&fields, binding.JSON)
+		mapper.ApplyProfileToApplicationRequest(user, &req, fields)
+	}
+
+	if err := h.markets.Validate(c.GetHeader(MarketHeader), req); err != nil {
 		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
 		return
 	}
 
-	app, err := h.svc.SubmitApplication(c.Request.Context(), mapper.MapApplicationRequestToDTO(req))
+	var quotaWindow time.Time
+	if isMerchant {
+		var err error
+		quotaWindow, err = h.merchants.ConsumeApplicationQuota(c.Request.Context(), principal.APIKeyID)
+		if err != nil {
+			if errors.Is(err, services.ErrQuotaExceeded) {
+				c.JSON(http.StatusTooManyRequests, exchange.NewErrorResponse(err.Error()))
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+			return
+		}
+	}
+
+	app := mapper.MapApplicationRequestToDTO(req)
+	app.UserID = userID
+	app.MerchantID = merchantID
+	app, err := h.svc.SubmitApplication(c.Request.Context(), app)
+	if isMerchant && (err != nil || app.Duplicate) {
+		// Only created applications count against the quota, also if the client went away.
+		h.merchants.RefundApplicationQuota(context.WithoutCancel(c.Request.Context()), principal.APIKeyID, quotaWindow)
+	}
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateApplication) {
+			c.JSON(http.StatusConflict, exchange.NewErrorResponse(err.Error()))
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
 		return
 	}
@@ -60,13 +137,17 @@ func (h *ApplicationHandler) SubmitApplication(c *gin.Context) {
 // GetApplication
 //
 // @Summary		Get application by ID
-// @Description Returns application details and offers for the given application ID.
+// @Description Returns application details and offers for the given application ID. Offers are
+// @Description ranked from best to worst and the best one is marked as recommended. Only the user
+// @Description or merchant that submitted the application, or an admin, can read it.
 // @Security 	BearerAuth
 // @Tags		applications
 // @Produce 	json
 // @Param 		id path string true "Application ID"
+// @Param 		sort query string false "Criterion to sort offers by" Enums(apr, totalRepayment, monthlyPayment)
 // @Success 	200 {object} exchange.ApplicationResponse
 // @Failure 	400 {object} exchange.ErrorResponse
+// @Failure 	404 {object} exchange.ErrorResponse
 // @Failure 	500 {object} exchange.ErrorResponse
 // @Router 		/applications/{id} [get]
 func (h *ApplicationHandler) GetApplication(c *gin.Context) {
@@ -76,12 +157,17 @@ func (h *ApplicationHandler) GetApplication(c *gin.Context) {
 		return
 	}
 
-	app, err := h.svc.GetApplication(c.Request.Context(), id)
+	principal, _ := auth.FromContext(c)
+	app, err := h.svc.GetApplication(c.Request.Context(), principal, id, c.Query("sort"))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, exchange.NewErrorResponse("application not found"))
 			return
 		}
+		if errors.Is(err, ranking.ErrUnknownCriterion) {
+			c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+			return
+		}
 
 		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
 		return
@@ -89,3 +175,75 @@ func (h *ApplicationHandler) GetApplication(c *gin.Context) {
 
 	c.JSON(http.StatusOK, mapper.MapApplicationDTOToResponse(app))
 }
+
+// GetApplicationTimeline
+//
+// @Summary		Get application timeline
+// @Description Returns submission, bank response, offer status change and broadcast events
+// @Description of the application in chronological order. Only the user or merchant that
+// @Description submitted the application, or an admin, can read it.
+// @Security 	BearerAuth
+// @Tags		applications
+// @Produce 	json
+// @Param 		id path string true "Application ID"
+// @Success 	200 {object} exchange.TimelineResponse
+// @Failure 	400 {object} exchange.ErrorResponse
+// @Failure 	404 {object} exchange.ErrorResponse
+// @Failure 	500 {object} exchange.ErrorResponse
+// @Router 		/applications/{id}/timeline [get]
+func (h *ApplicationHandler) GetApplicationTimeline(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse("application id is required"))
+		return
+	}
+
+	principal, _ := auth.FromContext(c)
+	timeline, err := h.svc.GetApplicationTimeline(c.Request.Context(), principal, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, exchange.NewErrorResponse("application not found"))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapTimelineToResponse(id, timeline))
+}
+
+// GetOfferSchedule
+//
+// @Summary		Get offer repayment schedule
+// @Description Returns the repayment schedule of a processed offer: due dates starting at the first
+// @Description repayment date, principal and interest parts of every payment and the remaining balance.
+// @Description Only the user or merchant that submitted the application, or an admin, can read it.
+// @Security 	BearerAuth
+// @Tags		applications
+// @Produce 	json
+// @Param 		id path string true "Application ID"
+// @Param 		offerId path string true "Offer ID"
+// @Success 	200 {object} exchange.ScheduleResponse
+// @Failure 	404 {object} exchange.ErrorResponse
+// @Failure 	409 {object} exchange.ErrorResponse
+// @Failure 	500 {object} exchange.ErrorResponse
+// @Router 		/applications/{id}/offers/{offerId}/schedule [get]
+func (h *ApplicationHandler) GetOfferSchedule(c *gin.Context) {
+	offerID := c.Param("offerId")
+	principal, _ := auth.FromContext(c)
+	schedule, err := h.svc.GetOfferSchedule(c.Request.Context(), principal, c.Param("id"), offerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, exchange.NewErrorResponse("offer not found"))
+		case errors.Is(err, services.ErrNoRepaymentTerms):
+			c.JSON(http.StatusConflict, exchange.NewErrorResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapScheduleToResponse(offerID, schedule))
+}
@@ -0,0 +1,329 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
+	mock_banks "financing-aggregator/internal/mocks/banks"
+	mock_repositories "financing-aggregator/internal/mocks/repositories"
+	mock_services "financing-aggregator/internal/mocks/services"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/money"
+	"financing-aggregator/internal/ranking"
+	"financing-aggregator/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Test principals are authenticated by these headers instead of tokens.
+const (
+	subjectHeader  = "X-Subject"
+	scopeHeader    = "X-Scope"
+	apiKeyIDHeader = "X-API-Key-ID"
+)
+
+// applicationHandlerTestSuite runs the handler with the application service, so that
+// ownership checks are covered from the request to the repository.
+type applicationHandlerTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	cfg                   *config.Config
+	applicationRepository *mock_repositories.MockApplicationRepository
+	offerEventRepository  *mock_repositories.MockOfferEventRepository
+	merchantRepository    *mock_repositories.MockMerchantRepository
+	merchantService       *mock_services.MockMerchantService
+	userService           *mock_services.MockUserService
+	router                *gin.Engine
+}
+
+func TestApplicationHandlerSuite(t *testing.T) {
+	suite.Run(t, new(applicationHandlerTestSuite))
+}
+
+func (s *applicationHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.ctrl = gomock.NewController(s.T())
+	s.applicationRepository = mock_repositories.NewMockApplicationRepository(s.ctrl)
+	s.offerEventRepository = mock_repositories.NewMockOfferEventRepository(s.ctrl)
+	s.merchantRepository = mock_repositories.NewMockMerchantRepository(s.ctrl)
+	s.merchantService = mock_services.NewMockMerchantService(s.ctrl)
+	s.userService = mock_services.NewMockUserService(s.ctrl)
+	s.cfg = &config.Config{}
+
+	bank := mock_banks.NewMockBank(s.ctrl)
+	bank.EXPECT().Name().Return("bank1").AnyTimes()
+
+	svc, err := services.NewApplicationService(
+		zap.NewNop(),
+		s.cfg,
+		[]banks.Bank{bank},
+		nil,
+		lo.Must(ranking.New(nil)),
+		s.applicationRepository,
+		mock_repositories.NewMockOfferRepository(s.ctrl),
+		mock_repositories.NewMockSubmissionRepository(s.ctrl),
+		s.offerEventRepository,
+		s.merchantRepository,
+		nil,
+	)
+	s.Require().NoError(err)
+	markets, err := NewMarketValidator(getTestMarketsConfig())
+	s.Require().NoError(err)
+	handler := NewApplicationHandler(svc, s.userService, s.merchantService, markets)
+
+	s.router = gin.New()
+	s.router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader(subjectHeader); subject != "" {
+			c.Set(auth.PrincipalKey, auth.Principal{
+				Subject:  subject,
+				Scopes:   []string{c.GetHeader(scopeHeader)},
+				APIKeyID: c.GetHeader(apiKeyIDHeader),
+			})
+		}
+	})
+	s.router.POST("/api/applications", handler.SubmitApplication)
+	s.router.GET("/api/applications/:id", handler.GetApplication)
+	s.router.GET("/api/applications/:id/timeline", handler.GetApplicationTimeline)
+}
+
+func (s *applicationHandlerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *applicationHandlerTestSuite) Test_SubmitApplication_Quota() {
+	merchantID := uuid.New()
+	principal := auth.Principal{Subject: merchantID.String(), Scopes: []string{auth.ScopeMerchant}, APIKeyID: uuid.NewString()}
+	windowStart := time.Now().Truncate(time.Hour)
+	s.cfg.Duplicates = config.DuplicatesConfig{Window: time.Hour, Policy: services.DuplicatePolicyReturn}
+
+	s.Run("quota kept for created application", func() {
+		s.merchantService.EXPECT().ConsumeApplicationQuota(gomock.Any(), principal.APIKeyID).Return(windowStart, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID.String()).Return(models.Merchant{ID: merchantID}, nil)
+		s.applicationRepository.EXPECT().CreateUnlessDuplicate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		actual := s.post("/api/applications", getTestApplicationRequest("+37122334455"), principal)
+		s.Equal(http.StatusOK, actual.Code)
+	})
+
+	s.Run("quota refunded because application is a duplicate", func() {
+		existing := getTestApplicationModel(&merchantID, nil)
+		s.merchantService.EXPECT().ConsumeApplicationQuota(gomock.Any(), principal.APIKeyID).Return(windowStart, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID.String()).Return(models.Merchant{ID: merchantID}, nil)
+		s.applicationRepository.EXPECT().CreateUnlessDuplicate(gomock.Any(), gomock.Any(), gomock.Any()).Return(&existing, nil)
+		s.merchantService.EXPECT().RefundApplicationQuota(gomock.Any(), principal.APIKeyID, windowStart)
+
+		actual := s.post("/api/applications", getTestApplicationRequest("+37122334455"), principal)
+		s.Equal(http.StatusOK, actual.Code)
+		s.Contains(actual.Body.String(), `"duplicate":true`)
+	})
+
+	s.Run("quota refunded because currency is unsupported", func() {
+		req := getTestApplicationRequest("+37122334455")
+		req.Currency = "USD"
+		s.merchantService.EXPECT().ConsumeApplicationQuota(gomock.Any(), principal.APIKeyID).Return(windowStart, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID.String()).Return(models.Merchant{ID: merchantID}, nil)
+		s.merchantService.EXPECT().RefundApplicationQuota(gomock.Any(), principal.APIKeyID, windowStart)
+
+		actual := s.post("/api/applications", req, principal)
+		s.Equal(http.StatusBadRequest, actual.Code)
+	})
+
+	s.Run("error occurs because application quota is exceeded", func() {
+		s.merchantService.EXPECT().ConsumeApplicationQuota(gomock.Any(), principal.APIKeyID).Return(time.Time{}, services.ErrQuotaExceeded)
+
+		actual := s.post("/api/applications", getTestApplicationRequest("+37122334455"), principal)
+		s.Equal(http.StatusTooManyRequests, actual.Code)
+	})
+}
+
+func (s *applicationHandlerTestSuite) Test_SubmitApplication_Owner() {
+	s.Run("error occurs because principal is neither user nor merchant", func() {
+		actual := s.post("/api/applications", getTestApplicationRequest("+37122334455"), auth.Principal{Subject: "anakin", Scopes: []string{"applications:write"}})
+		s.Equal(http.StatusForbidden, actual.Code)
+	})
+
+	s.Run("application submitted by admin", func() {
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, app *models.Application) error {
+				s.Nil(app.UserID)
+				s.Nil(app.MerchantID)
+				return nil
+			})
+
+		actual := s.post("/api/applications", getTestApplicationRequest("+37122334455"), auth.Principal{Subject: "obi-wan", Scopes: []string{auth.ScopeAdmin}})
+		s.Equal(http.StatusOK, actual.Code)
+	})
+}
+
+func (s *applicationHandlerTestSuite) Test_SubmitApplication_Profile() {
+	userID := uuid.New()
+	principal := auth.Principal{Subject: userID.String(), Scopes: []string{auth.ScopeUser}}
+	user := dto.UserDTO{
+		ID:    userID.String(),
+		Email: "anakin@skywalker.com",
+		Profile: dto.ProfileDTO{
+			Phone:                    "+37122334455",
+			MonthlyIncome:            money.MustParse("1000"),
+			MonthlyExpenses:          money.MustParse("300"),
+			MonthlyCreditLiabilities: money.MustParse("100"),
+			MaritalStatus:            "MARRIED",
+			Dependents:               2,
+		},
+	}
+
+	s.Run("missing fields taken from profile", func() {
+		s.userService.EXPECT().GetUser(gomock.Any(), userID.String()).Return(user, nil)
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, app *models.Application) error {
+				s.Equal("anakin@skywalker.com", app.Email)
+				s.Equal("+37122334455", app.Phone)
+				s.Equal(money.MustParse("300"), app.MonthlyExpenses)
+				s.Equal("MARRIED", app.MaritalStatus)
+				s.Equal(2, app.Dependents)
+				return nil
+			})
+
+		actual := s.post("/api/applications", map[string]any{"amount": 100, "maritalStatus": nil}, principal)
+		s.Equal(http.StatusOK, actual.Code)
+	})
+
+	s.Run("fields sent as zero kept", func() {
+		s.userService.EXPECT().GetUser(gomock.Any(), userID.String()).Return(user, nil)
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, app *models.Application) error {
+				s.Equal(money.MustParse("1000"), app.MonthlyIncome)
+				s.Equal(money.Amount(0), app.MonthlyExpenses)
+				s.Equal(money.Amount(0), app.MonthlyCreditLiabilities)
+				s.Equal(0, app.Dependents)
+				return nil
+			})
+
+		body := map[string]any{"amount": 100, "monthlyExpenses": 0, "monthlyCreditLiabilities": 0, "dependents": 0}
+		actual := s.post("/api/applications", body, principal)
+		s.Equal(http.StatusOK, actual.Code)
+	})
+}
+
+func (s *applicationHandlerTestSuite) Test_GetApplication() {
+	merchantID, userID := uuid.New(), uuid.New()
+	application := getTestApplicationModel(&merchantID, &userID)
+	id := application.ID.String()
+
+	s.Run("application returned to owners and admins", func() {
+		for _, principal := range []auth.Principal{
+			{Subject: userID.String(), Scopes: []string{auth.ScopeUser}},
+			{Subject: merchantID.String(), Scopes: []string{auth.ScopeMerchant}},
+			{Subject: "obi-wan", Scopes: []string{auth.ScopeAdmin}},
+		} {
+			s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), id).Return(application, nil)
+
+			actual := s.get("/api/applications/"+id, principal)
+			s.Equal(http.StatusOK, actual.Code)
+
+			var response exchange.ApplicationResponse
+			s.Require().NoError(json.Unmarshal(actual.Body.Bytes(), &response))
+			s.Equal(id, response.ID)
+			s.Equal(application.Email, response.Email)
+		}
+	})
+
+	s.Run("error occurs because application belongs to someone else", func() {
+		for _, principal := range []auth.Principal{
+			{Subject: uuid.NewString(), Scopes: []string{auth.ScopeUser}},
+			{Subject: uuid.NewString(), Scopes: []string{auth.ScopeMerchant}},
+			{Subject: "anakin"},
+		} {
+			s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), id).Return(application, nil)
+
+			actual := s.get("/api/applications/"+id, principal)
+			s.Equal(http.StatusNotFound, actual.Code)
+			s.NotContains(actual.Body.String(), application.Email)
+		}
+	})
+
+	s.Run("error occurs because application not found", func() {
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), id).Return(models.Application{}, gorm.ErrRecordNotFound)
+
+		actual := s.get("/api/applications/"+id, auth.Principal{Subject: "obi-wan", Scopes: []string{auth.ScopeAdmin}})
+		s.Equal(http.StatusNotFound, actual.Code)
+	})
+
+	s.Run("error occurs because application ID is malformed", func() {
+		actual := s.get("/api/applications/anakin", auth.Principal{Subject: "obi-wan", Scopes: []string{auth.ScopeAdmin}})
+		s.Equal(http.StatusNotFound, actual.Code)
+	})
+}
+
+func (s *applicationHandlerTestSuite) Test_GetApplicationTimeline() {
+	userID := uuid.New()
+	application := getTestApplicationModel(nil, &userID)
+	id := application.ID.String()
+
+	s.Run("timeline returned to owner", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(application, nil)
+		s.offerEventRepository.EXPECT().ListByApplication(gomock.Any(), id).Return(nil, nil)
+
+		actual := s.get("/api/applications/"+id+"/timeline", auth.Principal{Subject: userID.String(), Scopes: []string{auth.ScopeUser}})
+		s.Equal(http.StatusOK, actual.Code)
+	})
+
+	s.Run("error occurs because application belongs to someone else", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(application, nil)
+
+		actual := s.get("/api/applications/"+id+"/timeline", auth.Principal{Subject: uuid.NewString(), Scopes: []string{auth.ScopeUser}})
+		s.Equal(http.StatusNotFound, actual.Code)
+	})
+
+	s.Run("error occurs because application ID is malformed", func() {
+		actual := s.get("/api/applications/anakin/timeline", auth.Principal{Subject: "obi-wan", Scopes: []string{auth.ScopeAdmin}})
+		s.Equal(http.StatusNotFound, actual.Code)
+	})
+}
+
+func (s *applicationHandlerTestSuite) get(path string, principal auth.Principal) *httptest.ResponseRecorder {
+	return s.serve(httptest.NewRequest(http.MethodGet, path, nil), principal)
+}
+
+func (s *applicationHandlerTestSuite) post(path string, body any, principal auth.Principal) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	s.Require().NoError(err)
+	return s.serve(httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)), principal)
+}
+
+func (s *applicationHandlerTestSuite) serve(req *http.Request, principal auth.Principal) *httptest.ResponseRecorder {
+	req.Header.Set(subjectHeader, principal.Subject)
+	if len(principal.Scopes) > 0 {
+		req.Header.Set(scopeHeader, principal.Scopes[0])
+	}
+	req.Header.Set(apiKeyIDHeader, principal.APIKeyID)
+
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func getTestApplicationModel(merchantID, userID *uuid.UUID) models.Application {
+	return models.Application{
+		ID:         uuid.New(),
+		MerchantID: merchantID,
+		UserID:     userID,
+		Phone:      "+37122334455",
+		Email:      "anakin@skywalker.com",
+		Currency:   "EUR",
+	}
+}
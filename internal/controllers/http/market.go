@@ -0,0 +1,127 @@
+package http
+
+import (
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/exchange"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+// MarketHeader selects the market an application is submitted in.
+const MarketHeader = "X-Market"
+
+var ErrUnknownMarket = errors.New("unknown market")
+
+var maritalStatuses = []string{"SINGLE", "MARRIED", "DIVORCED", "COHABITING"}
+
+// MarketValidator validates application requests against the profile of the market they
+// are submitted in. Every profile gets its own validator, whose rules override the struct
+// tags of exchange.ApplicationRequest.
+type MarketValidator struct {
+	defaultMarket string
+	validators    map[string]*validator.Validate
+}
+
+func NewMarketValidator(cfg config.MarketsConfig) (*MarketValidator, error) {
+	fields := requestFields()
+	validators := make(map[string]*validator.Validate, len(cfg.Profiles))
+	for market, profile := range cfg.Profiles {
+		rules, err := profileRules(profile, fields)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s market profile", market)
+		}
+
+		validate := validator.New(validator.WithRequiredStructEnabled())
+		validate.RegisterStructValidationMapRules(rules, exchange.ApplicationRequest{})
+		validators[strings.ToLower(market)] = validate
+	}
+
+	defaultMarket := strings.ToLower(cfg.Default)
+	if _, ok := validators[defaultMarket]; !ok {
+		return nil, errors.Wrapf(ErrUnknownMarket, "default market %q", cfg.Default)
+	}
+
+	return &MarketValidator{
+		defaultMarket: defaultMarket,
+		validators:    validators,
+	}, nil
+}
+
+// Validate checks the request against the profile of the market, or of the default market
+// if market is empty.
+func (v *MarketValidator) Validate(market string, req exchange.ApplicationRequest) error {
+	if market == "" {
+		market = v.defaultMarket
+	}
+
+	validate, ok := v.validators[strings.ToLower(market)]
+	if !ok {
+		return errors.Wrap(ErrUnknownMarket, market)
+	}
+	return validate.Struct(req)
+}
+
+// profileRules returns validation rules of request fields, keyed by struct field name,
+// that differ from their struct tags in the market.
+func profileRules(profile config.MarketProfile, fields map[string]reflect.StructField) (map[string]string, error) {
+	rules := make(map[string]string)
+	for _, name := range profile.RequiredFields {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown required field %q", name)
+		}
+		rules[field.Name] = joinRules("required", field.Tag.Get("validate"))
+	}
+
+	if len(profile.MaritalStatuses) > 0 {
+		for _, status := range profile.MaritalStatuses {
+			if !slices.Contains(maritalStatuses, status) {
+				return nil, fmt.Errorf("unknown marital status %q", status)
+			}
+		}
+		rules["MaritalStatus"] = "oneof=" + strings.Join(profile.MaritalStatuses, " ")
+	}
+
+	phone := []string{"e164"}
+	if profile.PhonePrefix != "" {
+		phone = append(phone, "startswith="+profile.PhonePrefix)
+	}
+	if len(profile.PhoneLengths) > 0 {
+		lengths := make([]string, 0, len(profile.PhoneLengths))
+		for _, l := range profile.PhoneLengths {
+			lengths = append(lengths, fmt.Sprintf("len=%d", l))
+		}
+		phone = append(phone, strings.Join(lengths, "|"))
+	}
+	if slices.Contains(profile.RequiredFields, "phone") {
+		rules["Phone"] = joinRules("required", strings.Join(phone, ","))
+	} else {
+		rules["Phone"] = joinRules("omitempty", strings.Join(phone, ","))
+	}
+
+	return rules, nil
+}
+
+// requestFields returns fields of exchange.ApplicationRequest keyed by their JSON name.
+func requestFields() map[string]reflect.StructField {
+	typ := reflect.TypeOf(exchange.ApplicationRequest{})
+	fields := make(map[string]reflect.StructField, typ.NumField())
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		fields[name] = field
+	}
+	return fields
+}
+
+func joinRules(first, second string) string {
+	if second == "" {
+		return first
+	}
+	return first + "," + second
+}
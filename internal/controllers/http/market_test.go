@@ -0,0 +1,124 @@
+package http
+
+import (
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/money"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type marketValidatorTestSuite struct {
+	suite.Suite
+	validator *MarketValidator
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(marketValidatorTestSuite))
+}
+
+func (s *marketValidatorTestSuite) SetupTest() {
+	var err error
+	s.validator, err = NewMarketValidator(getTestMarketsConfig())
+	s.Require().NoError(err)
+}
+
+func (s *marketValidatorTestSuite) Test_NewMarketValidator() {
+	s.Run("error occurs because default market has no profile", func() {
+		cfg := getTestMarketsConfig()
+		cfg.Default = "FI"
+
+		_, err := NewMarketValidator(cfg)
+		s.ErrorIs(err, ErrUnknownMarket)
+	})
+
+	s.Run("error occurs because required field is unknown", func() {
+		cfg := getTestMarketsConfig()
+		cfg.Profiles["lv"] = config.MarketProfile{RequiredFields: []string{"shoeSize"}}
+
+		_, err := NewMarketValidator(cfg)
+		s.ErrorContains(err, "shoeSize")
+	})
+
+	s.Run("error occurs because marital status is unknown", func() {
+		cfg := getTestMarketsConfig()
+		cfg.Profiles["lv"] = config.MarketProfile{MaritalStatuses: []string{"WIDOWED"}}
+
+		_, err := NewMarketValidator(cfg)
+		s.ErrorContains(err, "WIDOWED")
+	})
+}
+
+func (s *marketValidatorTestSuite) Test_Validate() {
+	s.Run("default market used without header", func() {
+		s.NoError(s.validator.Validate("", getTestApplicationRequest("+37122334455")))
+		s.Error(s.validator.Validate("", getTestApplicationRequest("+37222334455")))
+	})
+
+	s.Run("phone validated by market rules", func() {
+		s.NoError(s.validator.Validate("EE", getTestApplicationRequest("+3725123456")))
+		s.NoError(s.validator.Validate("ee", getTestApplicationRequest("+37251234567")))
+		s.Error(s.validator.Validate("EE", getTestApplicationRequest("+37122334455")))
+		s.Error(s.validator.Validate("EE", getTestApplicationRequest("+372512345")))
+	})
+
+	s.Run("required fields validated by market rules", func() {
+		req := getTestApplicationRequest("+37122334455")
+		req.MonthlyIncome = 0
+
+		s.ErrorContains(s.validator.Validate("LV", req), "MonthlyIncome")
+	})
+
+	s.Run("optional phone validated only if present", func() {
+		s.NoError(s.validator.Validate("EE", getTestApplicationRequest("")))
+		s.ErrorContains(s.validator.Validate("LV", getTestApplicationRequest("")), "Phone")
+	})
+
+	s.Run("marital status validated by market rules", func() {
+		req := getTestApplicationRequest("+3725123456")
+		req.MaritalStatus = "COHABITING"
+
+		s.ErrorContains(s.validator.Validate("EE", req), "MaritalStatus")
+	})
+
+	s.Run("static rules still applied", func() {
+		req := getTestApplicationRequest("+37122334455")
+		req.Email = "not-an-email"
+
+		s.ErrorContains(s.validator.Validate("LV", req), "Email")
+	})
+
+	s.Run("error occurs because market is unknown", func() {
+		s.ErrorIs(s.validator.Validate("FI", getTestApplicationRequest("+35840123456")), ErrUnknownMarket)
+	})
+}
+
+// getTestMarketsConfig returns profiles with lowercase keys, as they are read by viper.
+func getTestMarketsConfig() config.MarketsConfig {
+	return config.MarketsConfig{
+		Default: "LV",
+		Profiles: map[string]config.MarketProfile{
+			"lv": {
+				PhonePrefix:    "+371",
+				PhoneLengths:   []int{12},
+				RequiredFields: []string{"phone", "email", "amount", "monthlyIncome"},
+			},
+			"ee": {
+				PhonePrefix:     "+372",
+				PhoneLengths:    []int{11, 12},
+				RequiredFields:  []string{"email", "amount"},
+				MaritalStatuses: []string{"SINGLE", "MARRIED", "DIVORCED"},
+			},
+		},
+	}
+}
+
+func getTestApplicationRequest(phone string) exchange.ApplicationRequest {
+	return exchange.ApplicationRequest{
+		Phone:         phone,
+		Email:         "anakin@skywalker.com",
+		MonthlyIncome: money.MustParse("1000"),
+		MaritalStatus: "SINGLE",
+		Amount:        money.MustParse("100"),
+	}
+}
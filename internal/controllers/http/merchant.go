@@ -0,0 +1,227 @@
+package http
+
+import (
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type MerchantHandler struct {
+	svc      services.MerchantService
+	validate *validator.Validate
+}
+
+func NewMerchantHandler(svc services.MerchantService) *MerchantHandler {
+	return &MerchantHandler{
+		svc:      svc,
+		validate: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+// CreateMerchant
+//
+// @Summary		Create a merchant
+// @Description Creates a merchant, whose checkout authenticates with API keys. Requires the admin scope.
+// @Security 	BearerAuth
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param		merchant body exchange.MerchantRequest true "Merchant request"
+// @Success		201 {object} exchange.MerchantResponse
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/admin/merchants [post]
+func (h *MerchantHandler) CreateMerchant(c *gin.Context) {
+	var req exchange.MerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	merchant, err := h.svc.CreateMerchant(c.Request.Context(), req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapper.MapMerchantDTOToResponse(merchant))
+}
+
+// GetMerchant
+//
+// @Summary		Get a merchant
+// @Description Returns the merchant with its banks and branding. Requires the admin scope.
+// @Security 	BearerAuth
+// @Tags		admin
+// @Produce		json
+// @Param 		id path string true "Merchant ID"
+// @Success		200 {object} exchange.MerchantResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/admin/merchants/{id} [get]
+func (h *MerchantHandler) GetMerchant(c *gin.Context) {
+	merchant, err := h.svc.GetMerchant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		merchantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapMerchantDTOToResponse(merchant))
+}
+
+// UpdateSettings
+//
+// @Summary		Update merchant settings
+// @Description Replaces the banks applications of the merchant are sent to and its branding. Banks
+// @Description must be configured, their base URL and headers, e.g. credentials of the merchant,
+// @Description override the bank config. All configured banks are used if the list is empty.
+// @Description Header values are not returned. Requires the admin scope.
+// @Security 	BearerAuth
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param 		id path string true "Merchant ID"
+// @Param		settings body exchange.MerchantSettingsRequest true "Merchant settings"
+// @Success		200 {object} exchange.MerchantResponse
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/admin/merchants/{id}/settings [put]
+func (h *MerchantHandler) UpdateSettings(c *gin.Context) {
+	var req exchange.MerchantSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	merchant, err := h.svc.UpdateSettings(c.Request.Context(), c.Param("id"), mapper.MapMerchantSettingsRequestToDTO(req))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownBank) {
+			c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+			return
+		}
+
+		merchantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapMerchantDTOToResponse(merchant))
+}
+
+// GetCurrentMerchant
+//
+// @Summary		Get the authenticated merchant
+// @Description Returns the merchant the API key belongs to, with its banks and branding.
+// @Security 	APIKeyAuth
+// @Tags		merchants
+// @Produce		json
+// @Success		200 {object} exchange.MerchantResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/merchants/me [get]
+func (h *MerchantHandler) GetCurrentMerchant(c *gin.Context) {
+	principal, _ := auth.FromContext(c)
+	merchantID, ok := principal.MerchantID()
+	if !ok {
+		c.JSON(http.StatusForbidden, exchange.NewErrorResponse("request was not authenticated with an API key"))
+		return
+	}
+
+	merchant, err := h.svc.GetMerchant(c.Request.Context(), merchantID)
+	if err != nil {
+		merchantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapMerchantDTOToResponse(merchant))
+}
+
+// CreateAPIKey
+//
+// @Summary		Create a merchant API key
+// @Description Generates an API key of the merchant with request and application quotas, 0 meaning
+// @Description no limit. The key is returned only in this response. Requires the admin scope.
+// @Security 	BearerAuth
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param 		id path string true "Merchant ID"
+// @Param		apiKey body exchange.APIKeyRequest true "API key request"
+// @Success		201 {object} exchange.APIKeyResponse
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/admin/merchants/{id}/api-keys [post]
+func (h *MerchantHandler) CreateAPIKey(c *gin.Context) {
+	var req exchange.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	apiKey, err := h.svc.CreateAPIKey(c.Request.Context(), c.Param("id"), req.RequestQuota, req.ApplicationQuota)
+	if err != nil {
+		merchantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapper.MapAPIKeyDTOToResponse(apiKey))
+}
+
+// RevokeAPIKey
+//
+// @Summary		Revoke a merchant API key
+// @Description Revokes the API key, which cannot be used anymore. Requires the admin scope.
+// @Security 	BearerAuth
+// @Tags		admin
+// @Param 		id path string true "Merchant ID"
+// @Param 		keyId path string true "API key ID"
+// @Success		204
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/admin/merchants/{id}/api-keys/{keyId} [delete]
+func (h *MerchantHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.svc.RevokeAPIKey(c.Request.Context(), c.Param("id"), c.Param("keyId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, exchange.NewErrorResponse("API key not found"))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func merchantError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, exchange.NewErrorResponse("merchant not found"))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type UserHandler struct {
+	svc      services.UserService
+	validate *validator.Validate
+}
+
+func NewUserHandler(svc services.UserService) *UserHandler {
+	return &UserHandler{
+		svc:      svc,
+		validate: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+// Register
+//
+// @Summary		Register a user
+// @Description Creates a user with the email, password and applicant profile. Profile fields are
+// @Description used for applications the user submits without them.
+// @Tags		users
+// @Accept		json
+// @Produce		json
+// @Param		user body exchange.RegistrationRequest true "Registration request"
+// @Success		201 {object} exchange.UserResponse
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		409 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/auth/register [post]
+func (h *UserHandler) Register(c *gin.Context) {
+	var req exchange.RegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	user, err := h.svc.Register(c.Request.Context(), req.Email, req.Password, mapper.MapProfileRequestToDTO(req.Profile))
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, exchange.NewErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapper.MapUserDTOToResponse(user))
+}
+
+// Login
+//
+// @Summary		Log in
+// @Description Checks the email and password of a user and returns a bearer token for the user.
+// @Tags		users
+// @Accept		json
+// @Produce		json
+// @Param		credentials body exchange.LoginRequest true "Login request"
+// @Success		200 {object} exchange.TokenResponse
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		401 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Failure		501 {object} exchange.ErrorResponse
+// @Router 		/auth/login [post]
+func (h *UserHandler) Login(c *gin.Context) {
+	var req exchange.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	token, err := h.svc.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, exchange.NewErrorResponse(err.Error()))
+		case errors.Is(err, auth.ErrIssuingDisabled):
+			c.JSON(http.StatusNotImplemented, exchange.NewErrorResponse(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapTokenDTOToResponse(token))
+}
+
+// GetCurrentUser
+//
+// @Summary		Get the logged-in user
+// @Description Returns the user the bearer token was issued to, with the applicant profile.
+// @Security 	BearerAuth
+// @Tags		users
+// @Produce		json
+// @Success		200 {object} exchange.UserResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/users/me [get]
+func (h *UserHandler) GetCurrentUser(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.svc.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapUserDTOToResponse(user))
+}
+
+// UpdateProfile
+//
+// @Summary		Update the applicant profile
+// @Description Replaces the applicant profile of the logged-in user.
+// @Security 	BearerAuth
+// @Tags		users
+// @Accept		json
+// @Produce		json
+// @Param		profile body exchange.ProfileRequest true "Applicant profile"
+// @Success		200 {object} exchange.UserResponse
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		403 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/users/me/profile [put]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req exchange.ProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	user, err := h.svc.UpdateProfile(c.Request.Context(), userID, mapper.MapProfileRequestToDTO(req))
+	if err != nil {
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.MapUserDTOToResponse(user))
+}
+
+// currentUserID returns the ID of the logged-in user, or responds with 403 if the token
+// was not issued to a user.
+func currentUserID(c *gin.Context) (string, bool) {
+	principal, _ := auth.FromContext(c)
+	userID, ok := principal.UserID()
+	if !ok {
+		c.JSON(http.StatusForbidden, exchange.NewErrorResponse("token was not issued to a user"))
+		return "", false
+	}
+	return userID, true
+}
+
+func userError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, exchange.NewErrorResponse("user not found"))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+)
+
+const (
+	signatureHeader    = "X-Signature"
+	maxWebhookBodySize = 1 << 20
+)
+
+type WebhookHandler struct {
+	svc services.ApplicationService
+}
+
+func NewWebhookHandler(svc services.ApplicationService) *WebhookHandler {
+	return &WebhookHandler{
+		svc: svc,
+	}
+}
+
+// ReceiveBankWebhook
+//
+// @Summary		Receive application status update from a bank
+// @Description Accepts a status change callback from a bank. The raw body must be signed with
+// @Description HMAC-SHA256 using the bank webhook secret and the hex-encoded signature passed in
+// @Description the X-Signature header. The payload has the same shape as the bank status response.
+// @Description Banks push to the merchant URL for offers created with the base URL or headers of a
+// @Description merchant, i.e. with the account the merchant has with the bank.
+// @Tags		webhooks
+// @Accept		json
+// @Param 		bank path string true "Bank name"
+// @Param 		merchantId path string false "Merchant ID, for merchant accounts at the bank"
+// @Param 		X-Signature header string true "HMAC-SHA256 signature of the body"
+// @Success		204
+// @Failure		400 {object} exchange.ErrorResponse
+// @Failure		401 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
+// @Router 		/webhooks/banks/{bank} [post]
+// @Router 		/webhooks/merchants/{merchantId}/banks/{bank} [post]
+func (h *WebhookHandler) ReceiveBankWebhook(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+
+	err = h.svc.HandleBankWebhook(c.Request.Context(), c.Param("merchantId"), c.Param("bank"), c.GetHeader(signatureHeader), body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownBank), errors.Is(err, banks.ErrWebhooksNotSupported):
+			c.JSON(http.StatusNotFound, exchange.NewErrorResponse("bank does not accept webhooks"))
+		case errors.Is(err, banks.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, exchange.NewErrorResponse(err.Error()))
+		case errors.Is(err, banks.ErrInvalidWebhookPayload):
+			c.JSON(http.StatusBadRequest, exchange.NewErrorResponse(err.Error()))
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, exchange.NewErrorResponse("offer not found"))
+		default:
+			c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/banks/declarative"
+	"financing-aggregator/internal/config"
+	mock_ws "financing-aggregator/internal/mocks/controllers/ws"
+	mock_repositories "financing-aggregator/internal/mocks/repositories"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/money"
+	"financing-aggregator/internal/ranking"
+	"financing-aggregator/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+// webhookHandlerTestSuite runs the handler with the application service and a declarative
+// bank, so that signatures are checked and offers updated as in production.
+type webhookHandlerTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	applicationRepository *mock_repositories.MockApplicationRepository
+	offerRepository       *mock_repositories.MockOfferRepository
+	offerEventRepository  *mock_repositories.MockOfferEventRepository
+	wsHandler             *mock_ws.MockWebSocketHandler
+	router                *gin.Engine
+}
+
+func TestWebhookHandlerSuite(t *testing.T) {
+	suite.Run(t, new(webhookHandlerTestSuite))
+}
+
+func (s *webhookHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.ctrl = gomock.NewController(s.T())
+	s.applicationRepository = mock_repositories.NewMockApplicationRepository(s.ctrl)
+	s.offerRepository = mock_repositories.NewMockOfferRepository(s.ctrl)
+	s.offerEventRepository = mock_repositories.NewMockOfferEventRepository(s.ctrl)
+	s.wsHandler = mock_ws.NewMockWebSocketHandler(s.ctrl)
+
+	bank, err := declarative.NewBank("bank1", getTestWebhookBankConfig(), zap.NewNop())
+	s.Require().NoError(err)
+
+	svc, err := services.NewApplicationService(
+		zap.NewNop(),
+		&config.Config{},
+		[]banks.Bank{bank},
+		nil,
+		lo.Must(ranking.New(nil)),
+		s.applicationRepository,
+		s.offerRepository,
+		mock_repositories.NewMockSubmissionRepository(s.ctrl),
+		s.offerEventRepository,
+		mock_repositories.NewMockMerchantRepository(s.ctrl),
+		s.wsHandler,
+	)
+	s.Require().NoError(err)
+
+	s.router = gin.New()
+	s.router.POST("/webhooks/banks/:bank", NewWebhookHandler(svc).ReceiveBankWebhook)
+}
+
+func (s *webhookHandlerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *webhookHandlerTestSuite) Test_ReceiveBankWebhook() {
+	body := []byte(`{"id":"ext-1","status":"PROCESSED","monthly":50,"total":150,"count":3,"apr":10,"firstDate":"2025-01-01"}`)
+	offer := models.Offer{ID: uuid.New(), ApplicationID: uuid.New(), Bank: "bank1", ExternalID: "ext-1", Status: "DRAFT", Currency: "EUR"}
+
+	s.Run("offer updated", func() {
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank1", "ext-1").Return([]models.Offer{offer}, nil)
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offer.ApplicationID.String()).
+			Return(models.Application{ID: offer.ApplicationID, Amount: money.MustParse("100")}, nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offer.ID.String(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, updated models.Offer) (bool, error) {
+				s.Equal("PROCESSED", updated.Status)
+				s.Equal(3, updated.NumberOfPayments)
+				return true, nil
+			})
+		s.offerEventRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offer.ApplicationID.String(), gomock.Any())
+
+		recorder := s.post(body, sign(body))
+		s.Equal(http.StatusNoContent, recorder.Code)
+	})
+
+	s.Run("update of settled offer ignored", func() {
+		settled := offer
+		settled.Status = "TIMED_OUT"
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank1", "ext-1").Return([]models.Offer{settled}, nil)
+
+		recorder := s.post(body, sign(body))
+		s.Equal(http.StatusNoContent, recorder.Code)
+	})
+
+	s.Run("error occurs because signature is invalid", func() {
+		recorder := s.post(body, sign([]byte(`{"id":"ext-1","status":"DECLINED"}`)))
+		s.Equal(http.StatusUnauthorized, recorder.Code)
+	})
+
+	s.Run("error occurs because signature is missing", func() {
+		recorder := s.post(body, "")
+		s.Equal(http.StatusUnauthorized, recorder.Code)
+	})
+
+	s.Run("error occurs because offer is unknown", func() {
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank1", "ext-1").Return(nil, nil)
+
+		recorder := s.post(body, sign(body))
+		s.Equal(http.StatusNotFound, recorder.Code)
+	})
+}
+
+func (s *webhookHandlerTestSuite) post(body []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/banks/bank1", bytes.NewReader(body))
+	req.Header.Set(signatureHeader, signature)
+
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func getTestWebhookBankConfig() config.BankConfig {
+	return config.BankConfig{
+		BaseURL: "http://localhost",
+		Submit:  config.BankEndpoint{Method: http.MethodPost, Path: "/applications"},
+		Status:  config.BankEndpoint{Method: http.MethodGet, Path: "/applications/{id}"},
+		Response: config.BankResponseMapping{
+			ID:                   "id",
+			Status:               "status",
+			MonthlyPaymentAmount: "monthly",
+			TotalRepaymentAmount: "total",
+			NumberOfPayments:     "count",
+			AnnualPercentageRate: "apr",
+			FirstRepaymentDate:   "firstDate",
+		},
+		Webhook: config.BankWebhookConfig{Secret: testWebhookSecret},
+	}
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/services"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a repeated request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware answers requests repeated with the same Idempotency-Key header with
+// the stored response to the first request instead of processing them again. Keys are
+// scoped to the authenticated caller, so keys of different callers never interfere. Reusing
+// a key with a different method, path or body is rejected with 422. Server errors, 409 and
+// 429 responses are not stored, as such requests may succeed later and can be retried with
+// the same key.
+func IdempotencyMiddleware(svc services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, exchange.NewErrorResponse("idempotency key is too long"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, exchange.NewErrorResponse("failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		principal, _ := auth.FromContext(c)
+		request := append([]byte(c.Request.Method+" "+c.FullPath()+"\n"), body...)
+		stored, err := svc.Begin(c.Request.Context(), principal.Subject, key, request)
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, exchange.NewErrorResponse(err.Error()))
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, exchange.NewErrorResponse(err.Error()))
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+			return
+		case stored != nil:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, gin.MIMEJSON+"; charset=utf-8", stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The request context may be canceled by now, the key must still be finished.
+		ctx := context.WithoutCancel(c.Request.Context())
+		if retriable(recorder.Status()) {
+			svc.Release(ctx, principal.Subject, key)
+			return
+		}
+		svc.Complete(ctx, principal.Subject, key, dto.IdempotentResponseDTO{StatusCode: recorder.Status(), Body: recorder.body.Bytes()})
+	}
+}
+
+// retriable reports whether the request may succeed if repeated later, e.g. once the quota
+// window resets or a conflicting request has finished.
+func retriable(status int) bool {
+	return status >= http.StatusInternalServerError || status == http.StatusConflict || status == http.StatusTooManyRequests
+}
+
+// responseRecorder keeps a copy of the response body written by handlers.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/dto"
+	mock_services "financing-aggregator/internal/mocks/services"
+	"financing-aggregator/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// subjectHeader carries the subject the test principal is authenticated as.
+const subjectHeader = "X-Subject"
+
+type idempotencyMiddlewareTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	idempotencyService *mock_services.MockIdempotencyService
+	router             *gin.Engine
+	handled            int
+	handlerStatus      int
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(idempotencyMiddlewareTestSuite))
+}
+
+func (s *idempotencyMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *idempotencyMiddlewareTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.idempotencyService = mock_services.NewMockIdempotencyService(s.ctrl)
+	s.handled = 0
+	s.handlerStatus = http.StatusOK
+
+	s.router = gin.New()
+	s.router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader(subjectHeader); subject != "" {
+			c.Set(auth.PrincipalKey, auth.Principal{Subject: subject})
+		}
+	})
+	s.router.POST("/api/applications", IdempotencyMiddleware(s.idempotencyService), func(c *gin.Context) {
+		s.handled++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(s.handlerStatus, "handled "+string(body))
+	})
+}
+
+func (s *idempotencyMiddlewareTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *idempotencyMiddlewareTestSuite) Test_IdempotencyMiddleware() {
+	s.Run("request without key processed", func() {
+		actual := s.post("", `{}`)
+		s.Equal(http.StatusOK, actual.Code)
+		s.Equal(1, s.handled)
+	})
+
+	s.Run("response stored for new request", func() {
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-1", []byte("POST /api/applications\n{}")).Return(nil, nil)
+		s.idempotencyService.EXPECT().Complete(gomock.Any(), "", "key-1", dto.IdempotentResponseDTO{StatusCode: http.StatusOK, Body: []byte("handled {}")})
+
+		actual := s.post("key-1", `{}`)
+		s.Equal(http.StatusOK, actual.Code)
+		s.Equal("handled {}", actual.Body.String())
+		s.Equal(2, s.handled)
+	})
+
+	s.Run("stored response replayed", func() {
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-1", gomock.Any()).
+			Return(&dto.IdempotentResponseDTO{StatusCode: http.StatusOK, Body: []byte(`{"id":"1"}`)}, nil)
+
+		actual := s.post("key-1", `{}`)
+		s.Equal(http.StatusOK, actual.Code)
+		s.Equal(`{"id":"1"}`, actual.Body.String())
+		s.Equal("true", actual.Header().Get(IdempotentReplayedHeader))
+		s.Equal(2, s.handled)
+	})
+
+	s.Run("key released after server error", func() {
+		s.handlerStatus = http.StatusInternalServerError
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-2", gomock.Any()).Return(nil, nil)
+		s.idempotencyService.EXPECT().Release(gomock.Any(), "", "key-2")
+
+		actual := s.post("key-2", `{}`)
+		s.Equal(http.StatusInternalServerError, actual.Code)
+	})
+
+	s.Run("key released after retriable client error", func() {
+		for _, status := range []int{http.StatusConflict, http.StatusTooManyRequests} {
+			s.handlerStatus = status
+			s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-2", gomock.Any()).Return(nil, nil)
+			s.idempotencyService.EXPECT().Release(gomock.Any(), "", "key-2")
+
+			actual := s.post("key-2", `{}`)
+			s.Equal(status, actual.Code)
+		}
+	})
+
+	s.Run("response stored after client error", func() {
+		s.handlerStatus = http.StatusBadRequest
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-4", gomock.Any()).Return(nil, nil)
+		s.idempotencyService.EXPECT().Complete(gomock.Any(), "", "key-4", gomock.Any())
+
+		actual := s.post("key-4", `{}`)
+		s.Equal(http.StatusBadRequest, actual.Code)
+	})
+
+	s.Run("key scoped to caller", func() {
+		s.handlerStatus = http.StatusOK
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "merchant-1", "key-3", []byte("POST /api/applications\n{}")).Return(nil, nil)
+		s.idempotencyService.EXPECT().Complete(gomock.Any(), "merchant-1", "key-3", gomock.Any())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/applications", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-3")
+		req.Header.Set(subjectHeader, "merchant-1")
+
+		actual := httptest.NewRecorder()
+		s.router.ServeHTTP(actual, req)
+		s.Equal(http.StatusOK, actual.Code)
+	})
+
+	s.Run("key reused with different body", func() {
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-1", gomock.Any()).Return(nil, services.ErrIdempotencyKeyReused)
+
+		actual := s.post("key-1", `{"amount": 1}`)
+		s.Equal(http.StatusUnprocessableEntity, actual.Code)
+	})
+
+	s.Run("request with key in progress", func() {
+		s.idempotencyService.EXPECT().Begin(gomock.Any(), "", "key-1", gomock.Any()).Return(nil, services.ErrIdempotencyKeyInProgress)
+
+		actual := s.post("key-1", `{}`)
+		s.Equal(http.StatusConflict, actual.Code)
+	})
+
+	s.Run("key too long", func() {
+		actual := s.post(strings.Repeat("k", 256), `{}`)
+		s.Equal(http.StatusBadRequest, actual.Code)
+	})
+}
+
+func (s *idempotencyMiddlewareTestSuite) post(key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/applications", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
@@ -1,20 +1,90 @@
 package controllers
 
 import (
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/services"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+const (
+	// AccessTokenParam carries the bearer token of WebSocket upgrades, as browsers cannot set
+	// headers on them.
+	AccessTokenParam = "access_token"
+	// APIKeyHeader carries the API key merchants authenticate with instead of a bearer token.
+	APIKeyHeader = "X-API-Key"
+)
+
+// AuthMiddleware authenticates the request by the merchant API key, if present, or by the
+// bearer token and stores the principal in the gin context under auth.PrincipalKey and in
+// the request context.
+func AuthMiddleware(verifier *auth.Verifier, merchants services.MerchantService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if !strings.HasPrefix(authHeader, "Bearer ") || len(authHeader) <= 7 {
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			principal, err := merchants.Authenticate(c.Request.Context(), key)
+			if err != nil {
+				switch {
+				case errors.Is(err, services.ErrInvalidAPIKey):
+					c.AbortWithStatusJSON(http.StatusUnauthorized, exchange.NewErrorResponse(err.Error()))
+				case errors.Is(err, services.ErrQuotaExceeded):
+					c.AbortWithStatusJSON(http.StatusTooManyRequests, exchange.NewErrorResponse(err.Error()))
+				default:
+					c.AbortWithStatusJSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+				}
+				return
+			}
+
+			setPrincipal(c, principal)
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c)
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, exchange.NewErrorResponse("invalid authorization header"))
 			return
 		}
+
+		principal, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, exchange.NewErrorResponse("invalid token"))
+			return
+		}
+
+		setPrincipal(c, principal)
 		c.Next()
 	}
 }
+
+// RequireScope rejects requests of principals without the scope with 403.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := auth.FromContext(c)
+		if !principal.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, exchange.NewErrorResponse("missing scope "+scope))
+			return
+		}
+		c.Next()
+	}
+}
+
+func setPrincipal(c *gin.Context, principal auth.Principal) {
+	c.Set(auth.PrincipalKey, principal)
+	c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" && strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		token := c.Query(AccessTokenParam)
+		return token, token != ""
+	}
+	if !strings.HasPrefix(authHeader, "Bearer ") || len(authHeader) <= 7 {
+		return "", false
+	}
+	return authHeader[7:], true
+}
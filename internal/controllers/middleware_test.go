@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/config"
+	mock_services "financing-aggregator/internal/mocks/services"
+	"financing-aggregator/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "a-test-secret-of-at-least-32-bytes"
+
+type authMiddlewareTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	merchantService *mock_services.MockMerchantService
+	router          *gin.Engine
+}
+
+func TestAuthSuite(t *testing.T) {
+	suite.Run(t, new(authMiddlewareTestSuite))
+}
+
+func (s *authMiddlewareTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.ctrl = gomock.NewController(s.T())
+	s.merchantService = mock_services.NewMockMerchantService(s.ctrl)
+
+	verifier, err := auth.New(config.AuthConfig{HS256Secret: testSecret})
+	s.Require().NoError(err)
+
+	s.router = gin.New()
+	s.router.Use(AuthMiddleware(verifier, s.merchantService))
+	s.router.GET("/api/applications", func(c *gin.Context) {
+		fromGin, _ := auth.FromContext(c)
+		fromRequest, _ := auth.FromContext(c.Request.Context())
+		c.String(http.StatusOK, fromGin.Subject+" "+fromRequest.Subject)
+	})
+	s.router.GET("/api/admin", RequireScope(auth.ScopeAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
+
+func (s *authMiddlewareTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *authMiddlewareTestSuite) Test_AuthMiddleware() {
+	s.Run("principal stored for valid token", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/applications", nil)
+		req.Header.Set("Authorization", "Bearer "+s.token(testSecret))
+
+		actual := s.serve(req)
+		s.Equal(http.StatusOK, actual.Code)
+		s.Equal("anakin anakin", actual.Body.String())
+	})
+
+	s.Run("token read from query of WebSocket upgrade", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/applications?access_token="+s.token(testSecret), nil)
+		req.Header.Set("Upgrade", "websocket")
+
+		actual := s.serve(req)
+		s.Equal(http.StatusOK, actual.Code)
+	})
+
+	s.Run("token not read from query of plain request", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/applications?access_token="+s.token(testSecret), nil)
+
+		actual := s.serve(req)
+		s.Equal(http.StatusUnauthorized, actual.Code)
+	})
+
+	s.Run("error occurs because header is missing", func() {
+		actual := s.serve(httptest.NewRequest(http.MethodGet, "/api/applications", nil))
+		s.Equal(http.StatusUnauthorized, actual.Code)
+	})
+
+	s.Run("error occurs because token is invalid", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/applications", nil)
+		req.Header.Set("Authorization", "Bearer "+s.token("other"))
+
+		actual := s.serve(req)
+		s.Equal(http.StatusUnauthorized, actual.Code)
+	})
+}
+
+func (s *authMiddlewareTestSuite) Test_AuthMiddleware_APIKey() {
+	s.Run("merchant stored for valid key", func() {
+		s.merchantService.EXPECT().Authenticate(gomock.Any(), "fak_key").
+			Return(auth.Principal{Subject: "merchant-1", Scopes: []string{auth.ScopeMerchant}, APIKeyID: "key-1"}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/applications", nil)
+		req.Header.Set(APIKeyHeader, "fak_key")
+
+		actual := s.serve(req)
+		s.Equal(http.StatusOK, actual.Code)
+		s.Equal("merchant-1 merchant-1", actual.Body.String())
+	})
+
+	s.Run("error occurs because key is invalid", func() {
+		s.merchantService.EXPECT().Authenticate(gomock.Any(), "fak_key").Return(auth.Principal{}, services.ErrInvalidAPIKey)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/applications", nil)
+		req.Header.Set(APIKeyHeader, "fak_key")
+		req.Header.Set("Authorization", "Bearer "+s.token(testSecret))
+
+		actual := s.serve(req)
+		s.Equal(http.StatusUnauthorized, actual.Code)
+	})
+
+	s.Run("error occurs because request quota is exceeded", func() {
+		s.merchantService.EXPECT().Authenticate(gomock.Any(), "fak_key").
+			Return(auth.Principal{}, errors.Wrap(services.ErrQuotaExceeded, "10 requests per 1m0s"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/applications", nil)
+		req.Header.Set(APIKeyHeader, "fak_key")
+
+		actual := s.serve(req)
+		s.Equal(http.StatusTooManyRequests, actual.Code)
+	})
+}
+
+func (s *authMiddlewareTestSuite) Test_RequireScope() {
+	s.Run("request with scope passed", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+s.token(testSecret, auth.ScopeAdmin))
+
+		actual := s.serve(req)
+		s.Equal(http.StatusOK, actual.Code)
+	})
+
+	s.Run("error occurs because scope is missing", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+s.token(testSecret, auth.ScopeUser))
+
+		actual := s.serve(req)
+		s.Equal(http.StatusForbidden, actual.Code)
+	})
+}
+
+func (s *authMiddlewareTestSuite) serve(req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *authMiddlewareTestSuite) token(secret string, scopes ...string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "anakin",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"scp": scopes,
+	})
+	signed, err := token.SignedString([]byte(secret))
+	s.Require().NoError(err)
+	return signed
+}
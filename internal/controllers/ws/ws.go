@@ -1,51 +1,233 @@
 package ws
 
 import (
+	"context"
+	"encoding/json"
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/repositories"
 	"go.uber.org/zap"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSubscriptions limits applications followed over one multiplexed connection.
+	maxSubscriptions = 100
+	maxCommandSize   = 4096
+	// listenRetryInterval is the wait before listening for offer updates again after a failure.
+	listenRetryInterval = 5 * time.Second
 )
 
 type WebSocketHandler interface {
+	Connect(c *gin.Context)
 	SubscribeToApplicationUpdates(c *gin.Context)
-	BroadcastNewOffer(appID string, offer exchange.OfferResponse)
+	BroadcastNewOffer(ctx context.Context, appID string, offer exchange.OfferResponse)
+	ListenForOffers(ctx context.Context)
 	CloseAll()
 }
 
+// client is a WebSocket connection. Writes are serialized, as a connection supports one
+// concurrent writer only.
+type client struct {
+	conn *websocket.Conn
+	// multiplexed clients receive offers wrapped in messages naming the application.
+	multiplexed bool
+
+	writeMu sync.Mutex
+}
+
+func (c *client) write(v any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
 type webSocketHandler struct {
-	mu          sync.RWMutex
-	logger      *zap.Logger
-	upgrader    websocket.Upgrader
-	connections map[string][]*websocket.Conn
+	mu              sync.RWMutex
+	logger          *zap.Logger
+	applicationRepo repositories.ApplicationRepository
+	offerUpdateRepo repositories.OfferUpdateRepository
+	upgrader        websocket.Upgrader
+	clients         map[*client]struct{}
+	connections     map[string][]*client
 }
 
-func NewWebSocketHandler(logger *zap.Logger) WebSocketHandler {
+func NewWebSocketHandler(
+	logger *zap.Logger,
+	applicationRepo repositories.ApplicationRepository,
+	offerUpdateRepo repositories.OfferUpdateRepository,
+) WebSocketHandler {
 	return &webSocketHandler{
-		logger: logger,
+		logger:          logger,
+		applicationRepo: applicationRepo,
+		offerUpdateRepo: offerUpdateRepo,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool { return true },
 		},
-		connections: make(map[string][]*websocket.Conn),
+		clients:     make(map[*client]struct{}),
+		connections: make(map[string][]*client),
 	}
 }
 
+// Connect
+//
+// @Summary		Upgrades the HTTP connection to a multiplexed WebSocket
+// @Description Upgrades the HTTP connection to a WebSocket following any number of applications.
+// @Description Clients send JSON commands: {"type": "subscribe", "id": "1", "applicationId": "..."},
+// @Description {"type": "unsubscribe", "id": "2", "applicationId": "..."} and {"type": "ping", "id": "3"}.
+// @Description Every command is answered with an "ack", "pong" or "error" message carrying the command ID.
+// @Description Offer updates are sent as {"type": "offer", "applicationId": "...", "offer": {...}}.
+// @Description Only the user or merchant that submitted an application, or an admin, can subscribe to it.
+// @Security 	BearerAuth
+// @Tags		wss
+// @Accept		json
+// @Param 		access_token query string false "Bearer token, if the Authorization header cannot be set"
+// @Param 		command body exchange.WebSocketCommand false "Commands sent over the connection"
+// @Success		101 {object} exchange.WebSocketMessage
+// @Router 		/ws [get]
+func (h *webSocketHandler) Connect(c *gin.Context) {
+	principal, _ := auth.FromContext(c)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upgrade connection"})
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxCommandSize)
+
+	cl := &client{conn: conn, multiplexed: true}
+	h.register(cl)
+	defer h.unregister(cl)
+
+	h.logger.Debug("connected to multiplexed updates", zap.String("subject", principal.Subject))
+
+	// subscriptions are only accessed by this goroutine.
+	subscriptions := make(map[string]struct{})
+	defer func() {
+		for appID := range subscriptions {
+			h.unsubscribe(appID, cl)
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var command exchange.WebSocketCommand
+		reply := exchange.WebSocketMessage{Type: exchange.WebSocketError, Error: "invalid command"}
+		if err := json.Unmarshal(data, &command); err == nil {
+			reply = h.handleCommand(c.Request.Context(), principal, cl, subscriptions, command)
+		}
+
+		if err := cl.write(reply); err != nil {
+			h.logger.Error("failed to write reply", zap.Error(err))
+			break
+		}
+	}
+}
+
+func (h *webSocketHandler) handleCommand(
+	ctx context.Context,
+	principal auth.Principal,
+	cl *client,
+	subscriptions map[string]struct{},
+	command exchange.WebSocketCommand,
+) exchange.WebSocketMessage {
+	fail := func(err string) exchange.WebSocketMessage {
+		return exchange.WebSocketMessage{Type: exchange.WebSocketError, ID: command.ID, ApplicationID: command.ApplicationID, Error: err}
+	}
+	ack := exchange.WebSocketMessage{Type: exchange.WebSocketAck, ID: command.ID, ApplicationID: command.ApplicationID}
+
+	switch command.Type {
+	case exchange.WebSocketPing:
+		return exchange.WebSocketMessage{Type: exchange.WebSocketPong, ID: command.ID}
+	case exchange.WebSocketSubscribe, exchange.WebSocketUnsubscribe:
+	default:
+		return fail("unknown command type")
+	}
+
+	appID := command.ApplicationID
+	if _, err := uuid.Parse(appID); err != nil {
+		return fail("valid applicationId is required")
+	}
+
+	if command.Type == exchange.WebSocketUnsubscribe {
+		if _, ok := subscriptions[appID]; ok {
+			delete(subscriptions, appID)
+			h.unsubscribe(appID, cl)
+		}
+		return ack
+	}
+
+	if _, ok := subscriptions[appID]; ok {
+		return ack
+	}
+	if len(subscriptions) >= maxSubscriptions {
+		return fail("too many subscriptions")
+	}
+
+	ok, err := h.canAccess(ctx, principal, appID)
+	if err != nil {
+		h.logger.Error("failed to subscribe to application updates", zap.String("applicationID", appID), zap.Error(err))
+		return fail("failed to subscribe")
+	}
+	if !ok {
+		return fail("application not found")
+	}
+
+	subscriptions[appID] = struct{}{}
+	h.subscribe(appID, cl)
+	h.logger.Debug("subscribed to application updates",
+		zap.String("applicationID", appID), zap.String("subject", principal.Subject))
+	return ack
+}
+
 // SubscribeToApplicationUpdates
 //
 // @Summary		Upgrades the HTTP connection to a WebSocket
 // @Description Upgrades the HTTP connection to a WebSocket and subscribes the client
 // @Description to real-time application updates. The client must provide the application ID
 // @Description as a URL parameter. The connection is kept open until the client disconnects or an error occurs.
+// @Description Only the user or merchant that submitted the application, or an admin, can subscribe.
+// @Description Use /ws to follow several applications over one connection.
 // @Security 	BearerAuth
 // @Tags		wss
 // @Accept		json
 // @Param 		id path string true "Application ID"
+// @Param 		access_token query string false "Bearer token, if the Authorization header cannot be set"
 // @Failure		400 {object} exchange.ErrorResponse
+// @Failure		404 {object} exchange.ErrorResponse
+// @Failure		500 {object} exchange.ErrorResponse
 // @Router 		/ws/applications/{id} [get]
 func (h *webSocketHandler) SubscribeToApplicationUpdates(c *gin.Context) {
+	appID := c.Param("id")
+	if appID == "" {
+		c.JSON(http.StatusBadRequest, exchange.NewErrorResponse("application id is required"))
+		return
+	}
+
+	principal, _ := auth.FromContext(c)
+	ok, err := h.canAccess(c.Request.Context(), principal, appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, exchange.NewErrorResponse(err.Error()))
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, exchange.NewErrorResponse("application not found"))
+		return
+	}
+
 	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upgrade connection"})
@@ -53,15 +235,14 @@ func (h *webSocketHandler) SubscribeToApplicationUpdates(c *gin.Context) {
 	}
 	defer conn.Close()
 
-	appID := c.Param("id")
-	if appID == "" {
-		conn.WriteJSON(exchange.NewErrorResponse("application id is required"))
-		return
-	}
+	h.logger.Debug("subscribed to application updates",
+		zap.String("applicationID", appID), zap.String("subject", principal.Subject))
 
-	h.mu.Lock()
-	h.connections[appID] = append(h.connections[appID], conn)
-	h.mu.Unlock()
+	cl := &client{conn: conn}
+	h.register(cl)
+	defer h.unregister(cl)
+	h.subscribe(appID, cl)
+	defer h.unsubscribe(appID, cl)
 
 	for {
 		_, _, err := conn.ReadMessage()
@@ -69,28 +250,113 @@ func (h *webSocketHandler) SubscribeToApplicationUpdates(c *gin.Context) {
 			break
 		}
 	}
+}
+
+// canAccess reports whether the application exists and the principal owns it or is an admin,
+// so that applications of others cannot be told from missing ones.
+func (h *webSocketHandler) canAccess(ctx context.Context, principal auth.Principal, appID string) (bool, error) {
+	if uuid.Validate(appID) != nil {
+		return false, nil
+	}
+
+	application, err := h.applicationRepo.Get(ctx, appID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return principal.CanAccess(application.MerchantID, application.UserID), nil
+}
 
+func (h *webSocketHandler) register(cl *client) {
 	h.mu.Lock()
-	conns := h.connections[appID]
-	for i, c := range conns {
-		if c == conn {
-			h.connections[appID] = append(conns[:i], conns[i+1:]...)
+	defer h.mu.Unlock()
+	h.clients[cl] = struct{}{}
+}
+
+func (h *webSocketHandler) unregister(cl *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, cl)
+}
+
+func (h *webSocketHandler) subscribe(appID string, cl *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connections[appID] = append(h.connections[appID], cl)
+}
+
+func (h *webSocketHandler) unsubscribe(appID string, cl *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := h.connections[appID]
+	for i, c := range clients {
+		if c == cl {
+			// Copied, as broadcasts may still iterate over the slice without the lock.
+			h.connections[appID] = append(clients[:i:i], clients[i+1:]...)
 			break
 		}
 	}
 	if len(h.connections[appID]) == 0 {
 		delete(h.connections, appID)
 	}
-	h.mu.Unlock()
 }
 
-func (h *webSocketHandler) BroadcastNewOffer(appID string, application exchange.OfferResponse) {
+// BroadcastNewOffer publishes the offer to all replicas, which send it to their subscribers
+// of the application. If publishing fails, only subscribers of this replica receive it.
+func (h *webSocketHandler) BroadcastNewOffer(ctx context.Context, appID string, offer exchange.OfferResponse) {
+	payload, err := json.Marshal(exchange.WebSocketMessage{Type: exchange.WebSocketOffer, ApplicationID: appID, Offer: &offer})
+	if err == nil {
+		err = h.offerUpdateRepo.Publish(ctx, payload)
+	}
+	if err != nil {
+		h.logger.Error("failed to publish application update", zap.Error(err), zap.String("applicationID", appID))
+		h.deliver(appID, offer)
+	}
+}
+
+// ListenForOffers sends offers published by any replica to subscribers of this replica until
+// ctx is done, listening again after failures.
+func (h *webSocketHandler) ListenForOffers(ctx context.Context) {
+	for {
+		err := h.offerUpdateRepo.Listen(ctx, h.receiveOffer)
+		if ctx.Err() != nil {
+			return
+		}
+		h.logger.Error("failed to listen for application updates", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+func (h *webSocketHandler) receiveOffer(payload []byte) {
+	var message exchange.WebSocketMessage
+	if err := json.Unmarshal(payload, &message); err != nil || message.Offer == nil {
+		h.logger.Error("received invalid application update", zap.ByteString("payload", payload))
+		return
+	}
+	h.deliver(message.ApplicationID, *message.Offer)
+}
+
+// deliver sends the offer to subscribers of the application connected to this replica.
+func (h *webSocketHandler) deliver(appID string, offer exchange.OfferResponse) {
 	h.mu.RLock()
-	conns := h.connections[appID]
+	clients := h.connections[appID]
 	h.mu.RUnlock()
 
-	for _, conn := range conns {
-		if err := conn.WriteJSON(application); err != nil {
+	for _, cl := range clients {
+		var message any = offer
+		if cl.multiplexed {
+			message = exchange.WebSocketMessage{Type: exchange.WebSocketOffer, ApplicationID: appID, Offer: &offer}
+		}
+
+		if err := cl.write(message); err != nil {
 			h.logger.Error("failed to write application update", zap.Error(err))
 			continue
 		}
@@ -100,10 +366,9 @@ func (h *webSocketHandler) BroadcastNewOffer(appID string, application exchange.
 func (h *webSocketHandler) CloseAll() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	for _, conns := range h.connections {
-		for _, conn := range conns {
-			_ = conn.Close()
-		}
+	for cl := range h.clients {
+		_ = cl.conn.Close()
 	}
-	h.connections = make(map[string][]*websocket.Conn)
+	h.clients = make(map[*client]struct{})
+	h.connections = make(map[string][]*client)
 }
@@ -1,20 +1,46 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/exchange"
+	mock_repositories "financing-aggregator/internal/mocks/repositories"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/money"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+)
+
+// Test principals are authenticated by these headers instead of tokens.
+const (
+	subjectHeader = "X-Subject"
+	scopeHeader   = "X-Scope"
 )
 
 type webSocketTestSuite struct {
 	suite.Suite
-	server   *httptest.Server
-	wsURL    string
-	wsServer WebSocketHandler
+	ctrl                  *gomock.Controller
+	applicationRepository *mock_repositories.MockApplicationRepository
+	offerUpdateRepository *mock_repositories.MockOfferUpdateRepository
+	server                *httptest.Server
+	wsURL                 string
+	multiplexedURL        string
+	wsServer              WebSocketHandler
+
+	// receiveOffer passes payloads to the handler as if they were published by any replica.
+	receiveOffer  func(payload []byte)
+	stopListening context.CancelFunc
 }
 
 func TestSuite(t *testing.T) {
@@ -23,29 +49,61 @@ func TestSuite(t *testing.T) {
 
 func (s *webSocketTestSuite) SetupSuite() {
 	gin.SetMode(gin.TestMode)
+	s.ctrl = gomock.NewController(s.T())
+	s.applicationRepository = mock_repositories.NewMockApplicationRepository(s.ctrl)
+	s.offerUpdateRepository = mock_repositories.NewMockOfferUpdateRepository(s.ctrl)
+
 	r := gin.New()
-	s.wsServer = NewWebSocketHandler(nil)
+	r.Use(func(c *gin.Context) {
+		if subject := c.GetHeader(subjectHeader); subject != "" {
+			c.Set(auth.PrincipalKey, auth.Principal{Subject: subject, Scopes: []string{c.GetHeader(scopeHeader)}})
+		}
+	})
+	s.wsServer = NewWebSocketHandler(zap.NewNop(), s.applicationRepository, s.offerUpdateRepository)
+	r.GET("/ws", s.wsServer.Connect)
 	r.GET("/ws/applications/:id", s.wsServer.SubscribeToApplicationUpdates)
 
 	ts := httptest.NewServer(r)
 	s.server = ts
-	s.wsURL = "ws" + ts.URL[4:] + "/ws/applications/test-app-id"
+	s.wsURL = "ws" + ts.URL[4:] + "/ws/applications/"
+	s.multiplexedURL = "ws" + ts.URL[4:] + "/ws"
+
+	listening := make(chan func([]byte))
+	s.offerUpdateRepository.EXPECT().Listen(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, handle func([]byte)) error {
+		listening <- handle
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var ctx context.Context
+	ctx, s.stopListening = context.WithCancel(context.Background())
+	go s.wsServer.ListenForOffers(ctx)
+	s.receiveOffer = <-listening
+
+	s.offerUpdateRepository.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, payload []byte) error {
+		s.receiveOffer(payload)
+		return nil
+	}).AnyTimes()
 }
 
 func (s *webSocketTestSuite) TearDownSuite() {
+	s.stopListening()
 	if s.server != nil {
 		s.server.Close()
 	}
 	if s.wsServer != nil {
 		s.wsServer.CloseAll()
 	}
+	s.ctrl.Finish()
 }
 
 func (s *webSocketTestSuite) Test_WebSocket_ConnectionAndMessage() {
+	appID := uuid.NewString()
 	offerResponse := getTestOfferResponse()
+	userID := uuid.New()
+	s.applicationRepository.EXPECT().Get(gomock.Any(), appID).Return(models.Application{UserID: &userID}, nil)
 
-	c, _, err := websocket.DefaultDialer.Dial(s.wsURL, map[string][]string{})
-	s.NoError(err)
+	c, _, err := websocket.DefaultDialer.Dial(s.wsURL+appID, principalHeader(userID.String(), auth.ScopeUser))
+	s.Require().NoError(err)
 	defer c.Close()
 
 	done := make(chan struct{})
@@ -63,15 +121,175 @@ func (s *webSocketTestSuite) Test_WebSocket_ConnectionAndMessage() {
 		}
 	}()
 
-	s.wsServer.BroadcastNewOffer("test-app-id", offerResponse)
+	s.wsServer.BroadcastNewOffer(context.Background(), appID, offerResponse)
 
 	<-done
 }
 
+func (s *webSocketTestSuite) Test_WebSocket_Authorization() {
+	merchantID, userID := uuid.New(), uuid.New()
+	application := models.Application{MerchantID: &merchantID, UserID: &userID}
+	ownedAppID := uuid.NewString()
+
+	s.Run("owners and admins subscribed", func() {
+		for _, header := range []http.Header{
+			principalHeader(merchantID.String(), auth.ScopeMerchant),
+			principalHeader(userID.String(), auth.ScopeUser),
+			principalHeader("obi-wan", auth.ScopeAdmin),
+		} {
+			s.applicationRepository.EXPECT().Get(gomock.Any(), ownedAppID).Return(application, nil)
+
+			c, _, err := websocket.DefaultDialer.Dial(s.wsURL+ownedAppID, header)
+			s.Require().NoError(err)
+			c.Close()
+		}
+	})
+
+	s.Run("error occurs because application belongs to someone else", func() {
+		for _, header := range []http.Header{
+			principalHeader(uuid.NewString(), auth.ScopeMerchant),
+			principalHeader(uuid.NewString(), auth.ScopeUser),
+			principalHeader(userID.String(), auth.ScopeMerchant),
+			principalHeader("anakin", ""),
+		} {
+			s.applicationRepository.EXPECT().Get(gomock.Any(), ownedAppID).Return(application, nil)
+
+			_, resp, err := websocket.DefaultDialer.Dial(s.wsURL+ownedAppID, header)
+			s.ErrorIs(err, websocket.ErrBadHandshake)
+			s.Equal(http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	s.Run("error occurs because application not found", func() {
+		missingAppID := uuid.NewString()
+		s.applicationRepository.EXPECT().Get(gomock.Any(), missingAppID).Return(models.Application{}, gorm.ErrRecordNotFound)
+
+		_, resp, err := websocket.DefaultDialer.Dial(s.wsURL+missingAppID, principalHeader("obi-wan", auth.ScopeAdmin))
+		s.ErrorIs(err, websocket.ErrBadHandshake)
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("error occurs because application ID is malformed", func() {
+		_, resp, err := websocket.DefaultDialer.Dial(s.wsURL+"test-app-id", principalHeader("obi-wan", auth.ScopeAdmin))
+		s.ErrorIs(err, websocket.ErrBadHandshake)
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("error occurs while getting application", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), ownedAppID).Return(models.Application{}, errors.New("db error"))
+
+		_, resp, err := websocket.DefaultDialer.Dial(s.wsURL+ownedAppID, principalHeader("obi-wan", auth.ScopeAdmin))
+		s.ErrorIs(err, websocket.ErrBadHandshake)
+		s.Equal(http.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func (s *webSocketTestSuite) Test_WebSocket_Multiplexed() {
+	userID := uuid.New()
+	application := models.Application{UserID: &userID}
+	appID1, appID2, otherAppID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	offerResponse := getTestOfferResponse()
+
+	c, _, err := websocket.DefaultDialer.Dial(s.multiplexedURL, principalHeader(userID.String(), auth.ScopeUser))
+	s.Require().NoError(err)
+	defer c.Close()
+
+	s.Run("ping answered", func() {
+		actual := s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketPing, ID: "1"})
+		s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketPong, ID: "1"}, actual)
+	})
+
+	s.Run("offers of subscribed applications received", func() {
+		for _, appID := range []string{appID1, appID2} {
+			s.applicationRepository.EXPECT().Get(gomock.Any(), appID).Return(application, nil)
+
+			actual := s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketSubscribe, ID: "2", ApplicationID: appID})
+			s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketAck, ID: "2", ApplicationID: appID}, actual)
+		}
+
+		s.wsServer.BroadcastNewOffer(context.Background(), appID1, offerResponse)
+		s.wsServer.BroadcastNewOffer(context.Background(), appID2, offerResponse)
+
+		for _, appID := range []string{appID1, appID2} {
+			s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketOffer, ApplicationID: appID, Offer: &offerResponse}, s.read(c))
+		}
+	})
+
+	s.Run("subscribing again acknowledged", func() {
+		actual := s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketSubscribe, ID: "3", ApplicationID: appID1})
+		s.Equal(exchange.WebSocketAck, actual.Type)
+	})
+
+	s.Run("offers of unsubscribed application not received", func() {
+		actual := s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketUnsubscribe, ID: "4", ApplicationID: appID2})
+		s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketAck, ID: "4", ApplicationID: appID2}, actual)
+
+		s.wsServer.BroadcastNewOffer(context.Background(), appID2, offerResponse)
+		s.wsServer.BroadcastNewOffer(context.Background(), appID1, offerResponse)
+		s.Equal(appID1, s.read(c).ApplicationID)
+	})
+
+	s.Run("offers published by another replica received", func() {
+		payload, err := json.Marshal(exchange.WebSocketMessage{Type: exchange.WebSocketOffer, ApplicationID: appID1, Offer: &offerResponse})
+		s.Require().NoError(err)
+
+		s.receiveOffer([]byte("not json"))
+		s.receiveOffer(payload)
+		s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketOffer, ApplicationID: appID1, Offer: &offerResponse}, s.read(c))
+	})
+
+	s.Run("error occurs because application belongs to someone else", func() {
+		otherUserID := uuid.New()
+		s.applicationRepository.EXPECT().Get(gomock.Any(), otherAppID).Return(models.Application{UserID: &otherUserID}, nil)
+
+		actual := s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketSubscribe, ID: "5", ApplicationID: otherAppID})
+		s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketError, ID: "5", ApplicationID: otherAppID, Error: "application not found"}, actual)
+
+		s.wsServer.BroadcastNewOffer(context.Background(), otherAppID, offerResponse)
+		s.Equal(exchange.WebSocketPong, s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketPing}).Type)
+	})
+
+	s.Run("error occurs while getting application", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), otherAppID).Return(models.Application{}, errors.New("db error"))
+
+		actual := s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketSubscribe, ID: "6", ApplicationID: otherAppID})
+		s.Equal(exchange.WebSocketError, actual.Type)
+		s.Equal("failed to subscribe", actual.Error)
+	})
+
+	s.Run("error occurs because command is invalid", func() {
+		s.Require().NoError(c.WriteMessage(websocket.TextMessage, []byte("subscribe")))
+		s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketError, Error: "invalid command"}, s.read(c))
+
+		actual := s.send(c, exchange.WebSocketCommand{Type: "publish", ID: "7"})
+		s.Equal(exchange.WebSocketMessage{Type: exchange.WebSocketError, ID: "7", Error: "unknown command type"}, actual)
+
+		actual = s.send(c, exchange.WebSocketCommand{Type: exchange.WebSocketSubscribe, ID: "8", ApplicationID: "test-app-id"})
+		s.Equal("valid applicationId is required", actual.Error)
+	})
+}
+
+func (s *webSocketTestSuite) send(c *websocket.Conn, command exchange.WebSocketCommand) exchange.WebSocketMessage {
+	s.Require().NoError(c.WriteJSON(command))
+	return s.read(c)
+}
+
+func (s *webSocketTestSuite) read(c *websocket.Conn) exchange.WebSocketMessage {
+	s.Require().NoError(c.SetReadDeadline(time.Now().Add(5 * time.Second)))
+
+	var message exchange.WebSocketMessage
+	s.Require().NoError(c.ReadJSON(&message))
+	return message
+}
+
+func principalHeader(subject, scope string) http.Header {
+	return http.Header{subjectHeader: {subject}, scopeHeader: {scope}}
+}
+
 func getTestOfferResponse() exchange.OfferResponse {
 	return exchange.OfferResponse{
-		MonthlyPaymentAmount: 50,
-		TotalRepaymentAmount: 150,
+		MonthlyPaymentAmount: money.MustParse("50"),
+		TotalRepaymentAmount: money.MustParse("150"),
 		NumberOfPayments:     3,
 		AnnualPercentageRate: 10.0,
 		FirstRepaymentDate:   "2025-01-01",
@@ -1,29 +1,70 @@
 package dto
 
+import (
+	"financing-aggregator/internal/money"
+	"time"
+)
+
 type (
 	ApplicationDTO struct {
 		ID                       string
+		UserID                   string
+		MerchantID               string
 		Phone                    string
 		Email                    string
-		Amount                   float64
-		MonthlyIncome            float64
-		MonthlyExpenses          float64
-		MonthlyCreditLiabilities float64
+		Amount                   money.Amount
+		Currency                 string
+		MonthlyIncome            money.Amount
+		MonthlyExpenses          money.Amount
+		MonthlyCreditLiabilities money.Amount
 		MaritalStatus            string
 		Dependents               int
 		AgreeToDataSharing       bool
 		AgreeToBeScored          bool
 		Offers                   []OfferDTO
+		// Duplicate is set if an existing application was returned instead of the submitted one.
+		Duplicate bool
+	}
+
+	// IdempotentResponseDTO is the stored response to a request with an idempotency key.
+	IdempotentResponseDTO struct {
+		StatusCode int
+		Body       []byte
+	}
+
+	InstallmentDTO struct {
+		Number           int
+		DueDate          time.Time
+		Payment          money.Amount
+		Principal        money.Amount
+		Interest         money.Amount
+		RemainingBalance money.Amount
+	}
+
+	OfferEventDTO struct {
+		Type      string
+		Bank      string
+		OfferID   string
+		Status    string
+		Details   string
+		CreatedAt time.Time
 	}
 
 	OfferDTO struct {
+		ID                   string
 		ExternalID           string
 		Status               string
 		Bank                 string
-		MonthlyPaymentAmount float64
-		TotalRepaymentAmount float64
+		MonthlyPaymentAmount money.Amount
+		TotalRepaymentAmount money.Amount
+		Currency             string
 		NumberOfPayments     int
 		AnnualPercentageRate float64
 		FirstRepaymentDate   string
+		Recommended          bool
+
+		ComputedAnnualPercentageRate float64
+		ComputedTotalRepaymentAmount money.Amount
+		Verification                 string
 	}
 )
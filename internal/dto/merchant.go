@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type (
+	MerchantDTO struct {
+		ID        string
+		Name      string
+		Banks     []MerchantBankDTO
+		Branding  BrandingDTO
+		CreatedAt time.Time
+	}
+
+	// MerchantSettingsDTO holds banks enabled for a merchant, all configured banks if empty,
+	// and its branding.
+	MerchantSettingsDTO struct {
+		Banks    []MerchantBankDTO
+		Branding BrandingDTO
+	}
+
+	// MerchantBankDTO enables a configured bank for a merchant. BaseURL and Headers override
+	// the bank config if set.
+	MerchantBankDTO struct {
+		Name    string
+		BaseURL string
+		Headers map[string]string
+	}
+
+	BrandingDTO struct {
+		DisplayName  string
+		LogoURL      string
+		PrimaryColor string
+	}
+
+	// APIKeyDTO describes a merchant API key. Key is set only when the key is created.
+	APIKeyDTO struct {
+		ID               string
+		MerchantID       string
+		Key              string
+		Prefix           string
+		RequestQuota     int
+		ApplicationQuota int
+		CreatedAt        time.Time
+	}
+)
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"financing-aggregator/internal/money"
+	"time"
+)
+
+type (
+	UserDTO struct {
+		ID      string
+		Email   string
+		Profile ProfileDTO
+	}
+
+	// ProfileDTO holds applicant details used for applications submitted without them.
+	ProfileDTO struct {
+		Phone                    string
+		MonthlyIncome            money.Amount
+		MonthlyExpenses          money.Amount
+		MonthlyCreditLiabilities money.Amount
+		MaritalStatus            string
+		Dependents               int
+	}
+
+	TokenDTO struct {
+		AccessToken string
+		ExpiresAt   time.Time
+	}
+)
@@ -1,37 +1,88 @@
 package exchange
 
+import (
+	"financing-aggregator/internal/money"
+	"time"
+)
+
 type ApplicationRequest struct {
-	Phone                    string  `json:"phone" validate:"e164,startswith=+371,len=12"`
-	Email                    string  `json:"email" validate:"email"`
-	MonthlyIncome            float64 `json:"monthlyIncome" validate:"gte=0"`
-	MonthlyExpenses          float64 `json:"monthlyExpenses" validate:"gte=0"`
-	MonthlyCreditLiabilities float64 `json:"monthlyCreditLiabilities" validate:"gte=0"`
-	MaritalStatus            string  `json:"maritalStatus" validate:"oneof=SINGLE MARRIED DIVORCED COHABITING"`
-	Dependents               int     `json:"dependents" validate:"gte=0"`
-	AgreeToDataSharing       bool    `json:"agreeToDataSharing"`
-	AgreeToBeScored          bool    `json:"agreeToBeScored"`
-	Amount                   float64 `json:"amount" validate:"gte=0"`
+	Phone                    string       `json:"phone" validate:"e164"`
+	Email                    string       `json:"email" validate:"email"`
+	MonthlyIncome            money.Amount `json:"monthlyIncome" validate:"gte=0" swaggertype:"number"`
+	MonthlyExpenses          money.Amount `json:"monthlyExpenses" validate:"gte=0" swaggertype:"number"`
+	MonthlyCreditLiabilities money.Amount `json:"monthlyCreditLiabilities" validate:"gte=0" swaggertype:"number"`
+	MaritalStatus            string       `json:"maritalStatus" validate:"oneof=SINGLE MARRIED DIVORCED COHABITING"`
+	Dependents               int          `json:"dependents" validate:"gte=0"`
+	AgreeToDataSharing       bool         `json:"agreeToDataSharing"`
+	AgreeToBeScored          bool         `json:"agreeToBeScored"`
+	Amount                   money.Amount `json:"amount" validate:"gte=0" swaggertype:"number"`
+	Currency                 string       `json:"currency" validate:"omitempty,iso4217"`
 }
 
 type ApplicationResponse struct {
 	ID                       string          `json:"id"`
+	UserID                   string          `json:"userId,omitempty"`
+	MerchantID               string          `json:"merchantId,omitempty"`
 	Phone                    string          `json:"phone"`
 	Email                    string          `json:"email"`
-	MonthlyIncome            float64         `json:"monthlyIncome"`
-	MonthlyExpenses          float64         `json:"monthlyExpenses"`
-	MonthlyCreditLiabilities float64         `json:"monthlyCreditLiabilities"`
+	MonthlyIncome            money.Amount    `json:"monthlyIncome" swaggertype:"number"`
+	MonthlyExpenses          money.Amount    `json:"monthlyExpenses" swaggertype:"number"`
+	MonthlyCreditLiabilities money.Amount    `json:"monthlyCreditLiabilities" swaggertype:"number"`
 	MaritalStatus            string          `json:"maritalStatus"`
 	Dependents               int             `json:"dependents"`
 	AgreeToDataSharing       bool            `json:"agreeToDataSharing"`
 	AgreeToBeScored          bool            `json:"agreeToBeScored"`
-	Amount                   float64         `json:"amount"`
+	Amount                   money.Amount    `json:"amount" swaggertype:"number"`
+	Currency                 string          `json:"currency"`
 	Offers                   []OfferResponse `json:"offers,omitempty"`
+	// Duplicate is set if the submitted application duplicates this, previously submitted, one.
+	Duplicate bool `json:"duplicate,omitempty"`
 }
 
 type OfferResponse struct {
-	MonthlyPaymentAmount float64 `json:"monthlyPaymentAmount"`
-	TotalRepaymentAmount float64 `json:"totalRepaymentAmount"`
-	NumberOfPayments     int     `json:"numberOfPayments"`
-	AnnualPercentageRate float64 `json:"annualPercentageRate"`
-	FirstRepaymentDate   string  `json:"firstRepaymentDate"`
+	ID                   string       `json:"id,omitempty"`
+	Bank                 string       `json:"bank"`
+	Status               string       `json:"status"`
+	MonthlyPaymentAmount money.Amount `json:"monthlyPaymentAmount" swaggertype:"number"`
+	TotalRepaymentAmount money.Amount `json:"totalRepaymentAmount" swaggertype:"number"`
+	Currency             string       `json:"currency"`
+	NumberOfPayments     int          `json:"numberOfPayments"`
+	AnnualPercentageRate float64      `json:"annualPercentageRate"`
+	FirstRepaymentDate   string       `json:"firstRepaymentDate"`
+	Recommended          bool         `json:"recommended"`
+
+	ComputedAnnualPercentageRate float64      `json:"computedAnnualPercentageRate,omitempty"`
+	ComputedTotalRepaymentAmount money.Amount `json:"computedTotalRepaymentAmount,omitempty" swaggertype:"number"`
+	// Verification is VERIFIED or MISMATCH if the stated figures diverge from computed ones.
+	Verification string `json:"verification,omitempty"`
+}
+
+type TimelineResponse struct {
+	ApplicationID string                  `json:"applicationId"`
+	Events        []TimelineEventResponse `json:"events"`
+}
+
+type TimelineEventResponse struct {
+	Type      string    `json:"type"`
+	Bank      string    `json:"bank,omitempty"`
+	OfferID   string    `json:"offerId,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ScheduleResponse struct {
+	OfferID       string                `json:"offerId"`
+	TotalPayment  money.Amount          `json:"totalPayment" swaggertype:"number"`
+	TotalInterest money.Amount          `json:"totalInterest" swaggertype:"number"`
+	Installments  []InstallmentResponse `json:"installments"`
+}
+
+type InstallmentResponse struct {
+	Number           int          `json:"number"`
+	DueDate          string       `json:"dueDate"`
+	Payment          money.Amount `json:"payment" swaggertype:"number"`
+	Principal        money.Amount `json:"principal" swaggertype:"number"`
+	Interest         money.Amount `json:"interest" swaggertype:"number"`
+	RemainingBalance money.Amount `json:"remainingBalance" swaggertype:"number"`
 }
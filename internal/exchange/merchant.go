@@ -0,0 +1,66 @@
+package exchange
+
+import "time"
+
+type MerchantRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// MerchantSettingsRequest holds banks enabled for the merchant, all configured banks if
+// empty, and its branding.
+type MerchantSettingsRequest struct {
+	Banks    []MerchantBankRequest `json:"banks" validate:"unique=Name,dive"`
+	Branding BrandingRequest       `json:"branding"`
+}
+
+// MerchantBankRequest enables a configured bank for the merchant. BaseURL and Headers, e.g.
+// credentials of the merchant, override the bank config if set.
+type MerchantBankRequest struct {
+	Name    string            `json:"name" validate:"required"`
+	BaseURL string            `json:"baseUrl" validate:"omitempty,url"`
+	Headers map[string]string `json:"headers"`
+}
+
+type BrandingRequest struct {
+	DisplayName  string `json:"displayName" validate:"max=255"`
+	LogoURL      string `json:"logoUrl" validate:"omitempty,url"`
+	PrimaryColor string `json:"primaryColor" validate:"omitempty,hexcolor"`
+}
+
+type MerchantResponse struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Banks     []MerchantBankResponse `json:"banks"`
+	Branding  BrandingResponse       `json:"branding"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// MerchantBankResponse lists only names of the headers, as their values are credentials.
+type MerchantBankResponse struct {
+	Name    string   `json:"name"`
+	BaseURL string   `json:"baseUrl,omitempty"`
+	Headers []string `json:"headers,omitempty"`
+}
+
+type BrandingResponse struct {
+	DisplayName  string `json:"displayName"`
+	LogoURL      string `json:"logoUrl"`
+	PrimaryColor string `json:"primaryColor"`
+}
+
+// APIKeyRequest holds quotas of a new API key, 0 means no limit.
+type APIKeyRequest struct {
+	RequestQuota     int `json:"requestQuota" validate:"gte=0"`
+	ApplicationQuota int `json:"applicationQuota" validate:"gte=0"`
+}
+
+type APIKeyResponse struct {
+	ID         string `json:"id"`
+	MerchantID string `json:"merchantId"`
+	// Key is returned only once, when the key is created.
+	Key              string    `json:"key,omitempty"`
+	Prefix           string    `json:"prefix"`
+	RequestQuota     int       `json:"requestQuota"`
+	ApplicationQuota int       `json:"applicationQuota"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
@@ -0,0 +1,48 @@
+package exchange
+
+import (
+	"financing-aggregator/internal/money"
+	"time"
+)
+
+type RegistrationRequest struct {
+	Email    string         `json:"email" validate:"required,email"`
+	Password string         `json:"password" validate:"required,min=8,max=72"`
+	Profile  ProfileRequest `json:"profile"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ProfileRequest holds applicant details used for applications submitted without them.
+type ProfileRequest struct {
+	Phone                    string       `json:"phone" validate:"omitempty,e164"`
+	MonthlyIncome            money.Amount `json:"monthlyIncome" validate:"gte=0" swaggertype:"number"`
+	MonthlyExpenses          money.Amount `json:"monthlyExpenses" validate:"gte=0" swaggertype:"number"`
+	MonthlyCreditLiabilities money.Amount `json:"monthlyCreditLiabilities" validate:"gte=0" swaggertype:"number"`
+	MaritalStatus            string       `json:"maritalStatus" validate:"omitempty,oneof=SINGLE MARRIED DIVORCED COHABITING"`
+	Dependents               int          `json:"dependents" validate:"gte=0"`
+}
+
+type UserResponse struct {
+	ID      string          `json:"id"`
+	Email   string          `json:"email"`
+	Profile ProfileResponse `json:"profile"`
+}
+
+type ProfileResponse struct {
+	Phone                    string       `json:"phone,omitempty"`
+	MonthlyIncome            money.Amount `json:"monthlyIncome" swaggertype:"number"`
+	MonthlyExpenses          money.Amount `json:"monthlyExpenses" swaggertype:"number"`
+	MonthlyCreditLiabilities money.Amount `json:"monthlyCreditLiabilities" swaggertype:"number"`
+	MaritalStatus            string       `json:"maritalStatus,omitempty"`
+	Dependents               int          `json:"dependents"`
+}
+
+type TokenResponse struct {
+	AccessToken string    `json:"accessToken"`
+	TokenType   string    `json:"tokenType"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
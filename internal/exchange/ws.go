@@ -0,0 +1,31 @@
+package exchange
+
+// Types of commands sent by clients of the multiplexed WebSocket.
+const (
+	WebSocketSubscribe   = "subscribe"
+	WebSocketUnsubscribe = "unsubscribe"
+	WebSocketPing        = "ping"
+)
+
+// Types of messages sent to clients of the multiplexed WebSocket.
+const (
+	WebSocketAck   = "ack"
+	WebSocketPong  = "pong"
+	WebSocketError = "error"
+	WebSocketOffer = "offer"
+)
+
+type WebSocketCommand struct {
+	Type string `json:"type" enums:"subscribe,unsubscribe,ping"`
+	// ID is echoed in the reply, so that clients can match replies to commands.
+	ID            string `json:"id,omitempty"`
+	ApplicationID string `json:"applicationId,omitempty"`
+}
+
+type WebSocketMessage struct {
+	Type          string         `json:"type" enums:"ack,pong,error,offer"`
+	ID            string         `json:"id,omitempty"`
+	ApplicationID string         `json:"applicationId,omitempty"`
+	Offer         *OfferResponse `json:"offer,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"financing-aggregator/internal/dto"
 	"financing-aggregator/internal/exchange"
 	"financing-aggregator/internal/models"
+	"github.com/google/uuid"
 )
 
 func MapApplicationRequestToDTO(in exchange.ApplicationRequest) dto.ApplicationDTO {
@@ -18,6 +19,7 @@ func MapApplicationRequestToDTO(in exchange.ApplicationRequest) dto.ApplicationD
 		AgreeToDataSharing:       in.AgreeToDataSharing,
 		AgreeToBeScored:          in.AgreeToBeScored,
 		Amount:                   in.Amount,
+		Currency:                 in.Currency,
 	}
 }
 
@@ -29,6 +31,8 @@ func MapApplicationDTOToResponse(in dto.ApplicationDTO) exchange.ApplicationResp
 
 	return exchange.ApplicationResponse{
 		ID:                 in.ID,
+		UserID:             in.UserID,
+		MerchantID:         in.MerchantID,
 		Phone:              in.Phone,
 		Email:              in.Email,
 		MonthlyIncome:      in.MonthlyIncome,
@@ -38,12 +42,16 @@ func MapApplicationDTOToResponse(in dto.ApplicationDTO) exchange.ApplicationResp
 		AgreeToDataSharing: in.AgreeToDataSharing,
 		AgreeToBeScored:    in.AgreeToBeScored,
 		Amount:             in.Amount,
+		Currency:           in.Currency,
 		Offers:             offers,
+		Duplicate:          in.Duplicate,
 	}
 }
 
 func MapApplicationDTOToModel(in dto.ApplicationDTO) models.Application {
 	return models.Application{
+		UserID:                   optionalUUID(in.UserID),
+		MerchantID:               optionalUUID(in.MerchantID),
 		Phone:                    in.Phone,
 		Email:                    in.Email,
 		MonthlyIncome:            in.MonthlyIncome,
@@ -54,6 +62,7 @@ func MapApplicationDTOToModel(in dto.ApplicationDTO) models.Application {
 		AgreeToDataSharing:       in.AgreeToDataSharing,
 		AgreeToBeScored:          in.AgreeToBeScored,
 		Amount:                   in.Amount,
+		Currency:                 in.Currency,
 	}
 }
 
@@ -65,6 +74,8 @@ func MapApplicationModelToDTO(in models.Application) dto.ApplicationDTO {
 
 	return dto.ApplicationDTO{
 		ID:                       in.ID.String(),
+		UserID:                   optionalUUIDString(in.UserID),
+		MerchantID:               optionalUUIDString(in.MerchantID),
 		Phone:                    in.Phone,
 		Email:                    in.Email,
 		MonthlyIncome:            in.MonthlyIncome,
@@ -75,16 +86,42 @@ func MapApplicationModelToDTO(in models.Application) dto.ApplicationDTO {
 		AgreeToDataSharing:       in.AgreeToDataSharing,
 		AgreeToBeScored:          in.AgreeToBeScored,
 		Amount:                   in.Amount,
+		Currency:                 in.Currency,
 		Offers:                   offers,
 	}
 }
 
 func MapOfferDTOToResponse(in dto.OfferDTO) exchange.OfferResponse {
 	return exchange.OfferResponse{
+		ID:                   in.ID,
+		Bank:                 in.Bank,
+		Status:               in.Status,
 		MonthlyPaymentAmount: in.MonthlyPaymentAmount,
 		TotalRepaymentAmount: in.TotalRepaymentAmount,
+		Currency:             in.Currency,
 		NumberOfPayments:     in.NumberOfPayments,
 		AnnualPercentageRate: in.AnnualPercentageRate,
 		FirstRepaymentDate:   in.FirstRepaymentDate,
+		Recommended:          in.Recommended,
+
+		ComputedAnnualPercentageRate: in.ComputedAnnualPercentageRate,
+		ComputedTotalRepaymentAmount: in.ComputedTotalRepaymentAmount,
+		Verification:                 in.Verification,
+	}
+}
+
+// optionalUUID returns nil for an empty or malformed ID.
+func optionalUUID(id string) *uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+func optionalUUIDString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
 	}
+	return id.String()
 }
@@ -0,0 +1,116 @@
+package mapper
+
+import (
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/models"
+	"maps"
+	"slices"
+)
+
+func MapMerchantModelToDTO(in models.Merchant) dto.MerchantDTO {
+	var merchantBanks []dto.MerchantBankDTO
+	for _, bank := range in.Banks {
+		merchantBanks = append(merchantBanks, dto.MerchantBankDTO{
+			Name:    bank.Name,
+			BaseURL: bank.BaseURL,
+			Headers: bank.Headers,
+		})
+	}
+
+	return dto.MerchantDTO{
+		ID:    in.ID.String(),
+		Name:  in.Name,
+		Banks: merchantBanks,
+		Branding: dto.BrandingDTO{
+			DisplayName:  in.Branding.DisplayName,
+			LogoURL:      in.Branding.LogoURL,
+			PrimaryColor: in.Branding.PrimaryColor,
+		},
+		CreatedAt: in.CreatedAt,
+	}
+}
+
+func MapMerchantDTOToResponse(in dto.MerchantDTO) exchange.MerchantResponse {
+	var merchantBanks []exchange.MerchantBankResponse
+	for _, bank := range in.Banks {
+		merchantBanks = append(merchantBanks, exchange.MerchantBankResponse{
+			Name:    bank.Name,
+			BaseURL: bank.BaseURL,
+			Headers: slices.Sorted(maps.Keys(bank.Headers)),
+		})
+	}
+
+	return exchange.MerchantResponse{
+		ID:    in.ID,
+		Name:  in.Name,
+		Banks: merchantBanks,
+		Branding: exchange.BrandingResponse{
+			DisplayName:  in.Branding.DisplayName,
+			LogoURL:      in.Branding.LogoURL,
+			PrimaryColor: in.Branding.PrimaryColor,
+		},
+		CreatedAt: in.CreatedAt,
+	}
+}
+
+func MapMerchantSettingsRequestToDTO(in exchange.MerchantSettingsRequest) dto.MerchantSettingsDTO {
+	var merchantBanks []dto.MerchantBankDTO
+	for _, bank := range in.Banks {
+		merchantBanks = append(merchantBanks, dto.MerchantBankDTO{
+			Name:    bank.Name,
+			BaseURL: bank.BaseURL,
+			Headers: bank.Headers,
+		})
+	}
+
+	return dto.MerchantSettingsDTO{
+		Banks: merchantBanks,
+		Branding: dto.BrandingDTO{
+			DisplayName:  in.Branding.DisplayName,
+			LogoURL:      in.Branding.LogoURL,
+			PrimaryColor: in.Branding.PrimaryColor,
+		},
+	}
+}
+
+// ApplyMerchantSettingsToModel replaces banks and branding of the merchant.
+func ApplyMerchantSettingsToModel(in dto.MerchantSettingsDTO, merchant *models.Merchant) {
+	merchant.Banks = nil
+	for _, bank := range in.Banks {
+		merchant.Banks = append(merchant.Banks, models.MerchantBank{
+			Name:    bank.Name,
+			BaseURL: bank.BaseURL,
+			Headers: bank.Headers,
+		})
+	}
+
+	merchant.Branding = models.MerchantBranding{
+		DisplayName:  in.Branding.DisplayName,
+		LogoURL:      in.Branding.LogoURL,
+		PrimaryColor: in.Branding.PrimaryColor,
+	}
+}
+
+func MapAPIKeyModelToDTO(in models.MerchantAPIKey) dto.APIKeyDTO {
+	return dto.APIKeyDTO{
+		ID:               in.ID.String(),
+		MerchantID:       in.MerchantID.String(),
+		Prefix:           in.Prefix,
+		RequestQuota:     in.RequestQuota,
+		ApplicationQuota: in.ApplicationQuota,
+		CreatedAt:        in.CreatedAt,
+	}
+}
+
+func MapAPIKeyDTOToResponse(in dto.APIKeyDTO) exchange.APIKeyResponse {
+	return exchange.APIKeyResponse{
+		ID:               in.ID,
+		MerchantID:       in.MerchantID,
+		Key:              in.Key,
+		Prefix:           in.Prefix,
+		RequestQuota:     in.RequestQuota,
+		ApplicationQuota: in.ApplicationQuota,
+		CreatedAt:        in.CreatedAt,
+	}
+}
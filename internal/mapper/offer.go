@@ -18,6 +18,7 @@ func MapOfferDTOToModel(in dto.OfferDTO, applicationID uuid.UUID) models.Offer {
 		Status:               in.Status,
 		MonthlyPaymentAmount: in.MonthlyPaymentAmount,
 		TotalRepaymentAmount: in.TotalRepaymentAmount,
+		Currency:             in.Currency,
 		NumberOfPayments:     in.NumberOfPayments,
 		AnnualPercentageRate: in.AnnualPercentageRate,
 		FirstRepaymentDate:   firstRepaymentDate,
@@ -26,13 +27,19 @@ func MapOfferDTOToModel(in dto.OfferDTO, applicationID uuid.UUID) models.Offer {
 
 func MapOfferModelToDTO(in models.Offer) dto.OfferDTO {
 	return dto.OfferDTO{
+		ID:                   in.ID.String(),
 		ExternalID:           in.ExternalID,
 		Bank:                 in.Bank,
 		Status:               in.Status,
 		MonthlyPaymentAmount: in.MonthlyPaymentAmount,
 		TotalRepaymentAmount: in.TotalRepaymentAmount,
+		Currency:             in.Currency,
 		NumberOfPayments:     in.NumberOfPayments,
 		AnnualPercentageRate: in.AnnualPercentageRate,
 		FirstRepaymentDate:   in.FirstRepaymentDate.Format(dateFormat),
+
+		ComputedAnnualPercentageRate: in.ComputedAnnualPercentageRate,
+		ComputedTotalRepaymentAmount: in.ComputedTotalRepaymentAmount,
+		Verification:                 in.Verification,
 	}
 }
@@ -0,0 +1,27 @@
+package mapper
+
+import (
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
+)
+
+func MapScheduleToResponse(offerID string, in []dto.InstallmentDTO) exchange.ScheduleResponse {
+	response := exchange.ScheduleResponse{
+		OfferID:      offerID,
+		Installments: make([]exchange.InstallmentResponse, 0, len(in)),
+	}
+
+	for _, i := range in {
+		response.TotalPayment += i.Payment
+		response.TotalInterest += i.Interest
+		response.Installments = append(response.Installments, exchange.InstallmentResponse{
+			Number:           i.Number,
+			DueDate:          i.DueDate.Format(dateFormat),
+			Payment:          i.Payment,
+			Principal:        i.Principal,
+			Interest:         i.Interest,
+			RemainingBalance: i.RemainingBalance,
+		})
+	}
+	return response
+}
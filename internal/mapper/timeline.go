@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/models"
+)
+
+func MapOfferEventModelToDTO(in models.OfferEvent) dto.OfferEventDTO {
+	var offerID string
+	if in.OfferID != nil {
+		offerID = in.OfferID.String()
+	}
+
+	return dto.OfferEventDTO{
+		Type:      in.Type,
+		Bank:      in.Bank,
+		OfferID:   offerID,
+		Status:    in.Status,
+		Details:   in.Details,
+		CreatedAt: in.CreatedAt,
+	}
+}
+
+func MapTimelineToResponse(applicationID string, in []dto.OfferEventDTO) exchange.TimelineResponse {
+	events := make([]exchange.TimelineEventResponse, 0, len(in))
+	for _, e := range in {
+		events = append(events, exchange.TimelineEventResponse{
+			Type:      e.Type,
+			Bank:      e.Bank,
+			OfferID:   e.OfferID,
+			Status:    e.Status,
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return exchange.TimelineResponse{
+		ApplicationID: applicationID,
+		Events:        events,
+	}
+}
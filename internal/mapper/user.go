@@ -0,0 +1,99 @@
+package mapper
+
+import (
+	"encoding/json"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
+	"financing-aggregator/internal/models"
+)
+
+func MapProfileRequestToDTO(in exchange.ProfileRequest) dto.ProfileDTO {
+	return dto.ProfileDTO{
+		Phone:                    in.Phone,
+		MonthlyIncome:            in.MonthlyIncome,
+		MonthlyExpenses:          in.MonthlyExpenses,
+		MonthlyCreditLiabilities: in.MonthlyCreditLiabilities,
+		MaritalStatus:            in.MaritalStatus,
+		Dependents:               in.Dependents,
+	}
+}
+
+func MapUserModelToDTO(in models.User) dto.UserDTO {
+	return dto.UserDTO{
+		ID:    in.ID.String(),
+		Email: in.Email,
+		Profile: dto.ProfileDTO{
+			Phone:                    in.Phone,
+			MonthlyIncome:            in.MonthlyIncome,
+			MonthlyExpenses:          in.MonthlyExpenses,
+			MonthlyCreditLiabilities: in.MonthlyCreditLiabilities,
+			MaritalStatus:            in.MaritalStatus,
+			Dependents:               in.Dependents,
+		},
+	}
+}
+
+// ApplyProfileToUserModel sets profile fields of the user.
+func ApplyProfileToUserModel(in dto.ProfileDTO, user *models.User) {
+	user.Phone = in.Phone
+	user.MonthlyIncome = in.MonthlyIncome
+	user.MonthlyExpenses = in.MonthlyExpenses
+	user.MonthlyCreditLiabilities = in.MonthlyCreditLiabilities
+	user.MaritalStatus = in.MaritalStatus
+	user.Dependents = in.Dependents
+}
+
+func MapUserDTOToResponse(in dto.UserDTO) exchange.UserResponse {
+	return exchange.UserResponse{
+		ID:    in.ID,
+		Email: in.Email,
+		Profile: exchange.ProfileResponse{
+			Phone:                    in.Profile.Phone,
+			MonthlyIncome:            in.Profile.MonthlyIncome,
+			MonthlyExpenses:          in.Profile.MonthlyExpenses,
+			MonthlyCreditLiabilities: in.Profile.MonthlyCreditLiabilities,
+			MaritalStatus:            in.Profile.MaritalStatus,
+			Dependents:               in.Profile.Dependents,
+		},
+	}
+}
+
+func MapTokenDTOToResponse(in dto.TokenDTO) exchange.TokenResponse {
+	return exchange.TokenResponse{
+		AccessToken: in.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   in.ExpiresAt,
+	}
+}
+
+// ApplyProfileToApplicationRequest fills fields the request was submitted without from
+// the profile. fields is the submitted JSON object, so that fields sent as 0 or "" are kept
+// and only missing or null ones are filled. Email is the email the user registered with.
+func ApplyProfileToApplicationRequest(user dto.UserDTO, req *exchange.ApplicationRequest, fields map[string]json.RawMessage) {
+	missing := func(name string) bool {
+		value, ok := fields[name]
+		return !ok || string(value) == "null"
+	}
+
+	if missing("email") {
+		req.Email = user.Email
+	}
+	if missing("phone") {
+		req.Phone = user.Profile.Phone
+	}
+	if missing("monthlyIncome") {
+		req.MonthlyIncome = user.Profile.MonthlyIncome
+	}
+	if missing("monthlyExpenses") {
+		req.MonthlyExpenses = user.Profile.MonthlyExpenses
+	}
+	if missing("monthlyCreditLiabilities") {
+		req.MonthlyCreditLiabilities = user.Profile.MonthlyCreditLiabilities
+	}
+	if missing("maritalStatus") {
+		req.MaritalStatus = user.Profile.MaritalStatus
+	}
+	if missing("dependents") {
+		req.Dependents = user.Profile.Dependents
+	}
+}
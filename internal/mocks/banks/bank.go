@@ -78,3 +78,92 @@ func (mr *MockBankMockRecorder) SubmitApplication(ctx, data interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitApplication", reflect.TypeOf((*MockBank)(nil).SubmitApplication), ctx, data)
 }
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Health mocks base method.
+func (m *MockHealthChecker) Health() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockHealthCheckerMockRecorder) Health() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockHealthChecker)(nil).Health))
+}
+
+// MockWebhookReceiver is a mock of WebhookReceiver interface.
+type MockWebhookReceiver struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookReceiverMockRecorder
+}
+
+// MockWebhookReceiverMockRecorder is the mock recorder for MockWebhookReceiver.
+type MockWebhookReceiverMockRecorder struct {
+	mock *MockWebhookReceiver
+}
+
+// NewMockWebhookReceiver creates a new mock instance.
+func NewMockWebhookReceiver(ctrl *gomock.Controller) *MockWebhookReceiver {
+	mock := &MockWebhookReceiver{ctrl: ctrl}
+	mock.recorder = &MockWebhookReceiverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookReceiver) EXPECT() *MockWebhookReceiverMockRecorder {
+	return m.recorder
+}
+
+// ParseWebhook mocks base method.
+func (m *MockWebhookReceiver) ParseWebhook(signature string, body []byte) (dto.OfferDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseWebhook", signature, body)
+	ret0, _ := ret[0].(dto.OfferDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseWebhook indicates an expected call of ParseWebhook.
+func (mr *MockWebhookReceiverMockRecorder) ParseWebhook(signature, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseWebhook", reflect.TypeOf((*MockWebhookReceiver)(nil).ParseWebhook), signature, body)
+}
+
+// PollingDisabled mocks base method.
+func (m *MockWebhookReceiver) PollingDisabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollingDisabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// PollingDisabled indicates an expected call of PollingDisabled.
+func (mr *MockWebhookReceiverMockRecorder) PollingDisabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollingDisabled", reflect.TypeOf((*MockWebhookReceiver)(nil).PollingDisabled))
+}
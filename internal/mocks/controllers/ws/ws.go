@@ -5,6 +5,7 @@
 package mock_ws
 
 import (
+	context "context"
 	exchange "financing-aggregator/internal/exchange"
 	reflect "reflect"
 
@@ -36,15 +37,15 @@ func (m *MockWebSocketHandler) EXPECT() *MockWebSocketHandlerMockRecorder {
 }
 
 // BroadcastNewOffer mocks base method.
-func (m *MockWebSocketHandler) BroadcastNewOffer(appID string, offer exchange.OfferResponse) {
+func (m *MockWebSocketHandler) BroadcastNewOffer(ctx context.Context, appID string, offer exchange.OfferResponse) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "BroadcastNewOffer", appID, offer)
+	m.ctrl.Call(m, "BroadcastNewOffer", ctx, appID, offer)
 }
 
 // BroadcastNewOffer indicates an expected call of BroadcastNewOffer.
-func (mr *MockWebSocketHandlerMockRecorder) BroadcastNewOffer(appID, offer interface{}) *gomock.Call {
+func (mr *MockWebSocketHandlerMockRecorder) BroadcastNewOffer(ctx, appID, offer interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BroadcastNewOffer", reflect.TypeOf((*MockWebSocketHandler)(nil).BroadcastNewOffer), appID, offer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BroadcastNewOffer", reflect.TypeOf((*MockWebSocketHandler)(nil).BroadcastNewOffer), ctx, appID, offer)
 }
 
 // CloseAll mocks base method.
@@ -59,6 +60,30 @@ func (mr *MockWebSocketHandlerMockRecorder) CloseAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAll", reflect.TypeOf((*MockWebSocketHandler)(nil).CloseAll))
 }
 
+// Connect mocks base method.
+func (m *MockWebSocketHandler) Connect(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Connect", c)
+}
+
+// Connect indicates an expected call of Connect.
+func (mr *MockWebSocketHandlerMockRecorder) Connect(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockWebSocketHandler)(nil).Connect), c)
+}
+
+// ListenForOffers mocks base method.
+func (m *MockWebSocketHandler) ListenForOffers(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListenForOffers", ctx)
+}
+
+// ListenForOffers indicates an expected call of ListenForOffers.
+func (mr *MockWebSocketHandlerMockRecorder) ListenForOffers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenForOffers", reflect.TypeOf((*MockWebSocketHandler)(nil).ListenForOffers), ctx)
+}
+
 // SubscribeToApplicationUpdates mocks base method.
 func (m *MockWebSocketHandler) SubscribeToApplicationUpdates(c *gin.Context) {
 	m.ctrl.T.Helper()
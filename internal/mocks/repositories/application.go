@@ -8,6 +8,7 @@ import (
 	context "context"
 	models "financing-aggregator/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -49,6 +50,36 @@ func (mr *MockApplicationRepositoryMockRecorder) Create(ctx, app interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApplicationRepository)(nil).Create), ctx, app)
 }
 
+// CreateUnlessDuplicate mocks base method.
+func (m *MockApplicationRepository) CreateUnlessDuplicate(ctx context.Context, app *models.Application, since time.Time) (*models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUnlessDuplicate", ctx, app, since)
+	ret0, _ := ret[0].(*models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUnlessDuplicate indicates an expected call of CreateUnlessDuplicate.
+func (mr *MockApplicationRepositoryMockRecorder) CreateUnlessDuplicate(ctx, app, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUnlessDuplicate", reflect.TypeOf((*MockApplicationRepository)(nil).CreateUnlessDuplicate), ctx, app, since)
+}
+
+// Get mocks base method.
+func (m *MockApplicationRepository) Get(ctx context.Context, id string) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockApplicationRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockApplicationRepository)(nil).Get), ctx, id)
+}
+
 // GetWithProcessedOffers mocks base method.
 func (m *MockApplicationRepository) GetWithProcessedOffers(ctx context.Context, id string) (models.Application, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,183 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/merchant.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "financing-aggregator/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMerchantRepository is a mock of MerchantRepository interface.
+type MockMerchantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchantRepositoryMockRecorder
+}
+
+// MockMerchantRepositoryMockRecorder is the mock recorder for MockMerchantRepository.
+type MockMerchantRepositoryMockRecorder struct {
+	mock *MockMerchantRepository
+}
+
+// NewMockMerchantRepository creates a new mock instance.
+func NewMockMerchantRepository(ctrl *gomock.Controller) *MockMerchantRepository {
+	mock := &MockMerchantRepository{ctrl: ctrl}
+	mock.recorder = &MockMerchantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchantRepository) EXPECT() *MockMerchantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockMerchantRepository) Create(ctx context.Context, merchant *models.Merchant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, merchant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMerchantRepositoryMockRecorder) Create(ctx, merchant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMerchantRepository)(nil).Create), ctx, merchant)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockMerchantRepository) CreateAPIKey(ctx context.Context, key *models.MerchantAPIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockMerchantRepositoryMockRecorder) CreateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockMerchantRepository)(nil).CreateAPIKey), ctx, key)
+}
+
+// DecrementUsage mocks base method.
+func (m *MockMerchantRepository) DecrementUsage(ctx context.Context, keyID, kind string, windowStart time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementUsage", ctx, keyID, kind, windowStart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecrementUsage indicates an expected call of DecrementUsage.
+func (mr *MockMerchantRepositoryMockRecorder) DecrementUsage(ctx, keyID, kind, windowStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementUsage", reflect.TypeOf((*MockMerchantRepository)(nil).DecrementUsage), ctx, keyID, kind, windowStart)
+}
+
+// DeleteUsageBefore mocks base method.
+func (m *MockMerchantRepository) DeleteUsageBefore(ctx context.Context, windowStart time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUsageBefore", ctx, windowStart)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUsageBefore indicates an expected call of DeleteUsageBefore.
+func (mr *MockMerchantRepositoryMockRecorder) DeleteUsageBefore(ctx, windowStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUsageBefore", reflect.TypeOf((*MockMerchantRepository)(nil).DeleteUsageBefore), ctx, windowStart)
+}
+
+// Get mocks base method.
+func (m *MockMerchantRepository) Get(ctx context.Context, id string) (models.Merchant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Merchant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMerchantRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMerchantRepository)(nil).Get), ctx, id)
+}
+
+// GetAPIKey mocks base method.
+func (m *MockMerchantRepository) GetAPIKey(ctx context.Context, id string) (models.MerchantAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKey", ctx, id)
+	ret0, _ := ret[0].(models.MerchantAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockMerchantRepositoryMockRecorder) GetAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockMerchantRepository)(nil).GetAPIKey), ctx, id)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockMerchantRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (models.MerchantAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(models.MerchantAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockMerchantRepositoryMockRecorder) GetAPIKeyByHash(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockMerchantRepository)(nil).GetAPIKeyByHash), ctx, keyHash)
+}
+
+// IncrementUsage mocks base method.
+func (m *MockMerchantRepository) IncrementUsage(ctx context.Context, keyID, kind string, windowStart time.Time, limit int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementUsage", ctx, keyID, kind, windowStart, limit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementUsage indicates an expected call of IncrementUsage.
+func (mr *MockMerchantRepositoryMockRecorder) IncrementUsage(ctx, keyID, kind, windowStart, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementUsage", reflect.TypeOf((*MockMerchantRepository)(nil).IncrementUsage), ctx, keyID, kind, windowStart, limit)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockMerchantRepository) RevokeAPIKey(ctx context.Context, merchantID, keyID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, merchantID, keyID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockMerchantRepositoryMockRecorder) RevokeAPIKey(ctx, merchantID, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockMerchantRepository)(nil).RevokeAPIKey), ctx, merchantID, keyID)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockMerchantRepository) UpdateSettings(ctx context.Context, merchant *models.Merchant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx, merchant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockMerchantRepositoryMockRecorder) UpdateSettings(ctx, merchant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockMerchantRepository)(nil).UpdateSettings), ctx, merchant)
+}
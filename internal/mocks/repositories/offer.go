@@ -9,6 +9,7 @@ import (
 	models "financing-aggregator/internal/models"
 	repositories "financing-aggregator/internal/repositories"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,6 +37,21 @@ func (m *MockOfferRepository) EXPECT() *MockOfferRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Claim mocks base method.
+func (m *MockOfferRepository) Claim(ctx context.Context, filter repositories.OfferListFilter, lease repositories.Lease) ([]models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, filter, lease)
+	ret0, _ := ret[0].([]models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockOfferRepositoryMockRecorder) Claim(ctx, filter, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockOfferRepository)(nil).Claim), ctx, filter, lease)
+}
+
 // Create mocks base method.
 func (m *MockOfferRepository) Create(ctx context.Context, offer *models.Offer) error {
 	m.ctrl.T.Helper()
@@ -50,31 +66,76 @@ func (mr *MockOfferRepositoryMockRecorder) Create(ctx, offer interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOfferRepository)(nil).Create), ctx, offer)
 }
 
-// List mocks base method.
-func (m *MockOfferRepository) List(ctx context.Context, filter repositories.OfferListFilter) ([]models.Offer, error) {
+// GetByApplication mocks base method.
+func (m *MockOfferRepository) GetByApplication(ctx context.Context, applicationID, id string) (models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByApplication", ctx, applicationID, id)
+	ret0, _ := ret[0].(models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByApplication indicates an expected call of GetByApplication.
+func (mr *MockOfferRepositoryMockRecorder) GetByApplication(ctx, applicationID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByApplication", reflect.TypeOf((*MockOfferRepository)(nil).GetByApplication), ctx, applicationID, id)
+}
+
+// ListByExternalID mocks base method.
+func (m *MockOfferRepository) ListByExternalID(ctx context.Context, bank, externalID string) ([]models.Offer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret := m.ctrl.Call(m, "ListByExternalID", ctx, bank, externalID)
 	ret0, _ := ret[0].([]models.Offer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockOfferRepositoryMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+// ListByExternalID indicates an expected call of ListByExternalID.
+func (mr *MockOfferRepositoryMockRecorder) ListByExternalID(ctx, bank, externalID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOfferRepository)(nil).List), ctx, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByExternalID", reflect.TypeOf((*MockOfferRepository)(nil).ListByExternalID), ctx, bank, externalID)
 }
 
-// Update mocks base method.
-func (m *MockOfferRepository) Update(ctx context.Context, id string, offer models.Offer) error {
+// Reschedule mocks base method.
+func (m *MockOfferRepository) Reschedule(ctx context.Context, id string, pollAttempts int, nextPollAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, offer)
+	ret := m.ctrl.Call(m, "Reschedule", ctx, id, pollAttempts, nextPollAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockOfferRepositoryMockRecorder) Update(ctx, id, offer interface{}) *gomock.Call {
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockOfferRepositoryMockRecorder) Reschedule(ctx, id, pollAttempts, nextPollAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockOfferRepository)(nil).Reschedule), ctx, id, pollAttempts, nextPollAt)
+}
+
+// TimeOut mocks base method.
+func (m *MockOfferRepository) TimeOut(ctx context.Context, bank string, createdBefore time.Time) ([]models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TimeOut", ctx, bank, createdBefore)
+	ret0, _ := ret[0].([]models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TimeOut indicates an expected call of TimeOut.
+func (mr *MockOfferRepositoryMockRecorder) TimeOut(ctx, bank, createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeOut", reflect.TypeOf((*MockOfferRepository)(nil).TimeOut), ctx, bank, createdBefore)
+}
+
+// UpdateDraft mocks base method.
+func (m *MockOfferRepository) UpdateDraft(ctx context.Context, id string, offer models.Offer) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDraft", ctx, id, offer)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDraft indicates an expected call of UpdateDraft.
+func (mr *MockOfferRepositoryMockRecorder) UpdateDraft(ctx, id, offer interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOfferRepository)(nil).Update), ctx, id, offer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDraft", reflect.TypeOf((*MockOfferRepository)(nil).UpdateDraft), ctx, id, offer)
 }
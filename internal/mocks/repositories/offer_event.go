@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/offer_event.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "financing-aggregator/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOfferEventRepository is a mock of OfferEventRepository interface.
+type MockOfferEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferEventRepositoryMockRecorder
+}
+
+// MockOfferEventRepositoryMockRecorder is the mock recorder for MockOfferEventRepository.
+type MockOfferEventRepositoryMockRecorder struct {
+	mock *MockOfferEventRepository
+}
+
+// NewMockOfferEventRepository creates a new mock instance.
+func NewMockOfferEventRepository(ctrl *gomock.Controller) *MockOfferEventRepository {
+	mock := &MockOfferEventRepository{ctrl: ctrl}
+	mock.recorder = &MockOfferEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferEventRepository) EXPECT() *MockOfferEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOfferEventRepository) Create(ctx context.Context, events []models.OfferEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOfferEventRepositoryMockRecorder) Create(ctx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOfferEventRepository)(nil).Create), ctx, events)
+}
+
+// ListByApplication mocks base method.
+func (m *MockOfferEventRepository) ListByApplication(ctx context.Context, applicationID string) ([]models.OfferEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByApplication", ctx, applicationID)
+	ret0, _ := ret[0].([]models.OfferEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByApplication indicates an expected call of ListByApplication.
+func (mr *MockOfferEventRepositoryMockRecorder) ListByApplication(ctx, applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByApplication", reflect.TypeOf((*MockOfferEventRepository)(nil).ListByApplication), ctx, applicationID)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/offer_update.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOfferUpdateRepository is a mock of OfferUpdateRepository interface.
+type MockOfferUpdateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferUpdateRepositoryMockRecorder
+}
+
+// MockOfferUpdateRepositoryMockRecorder is the mock recorder for MockOfferUpdateRepository.
+type MockOfferUpdateRepositoryMockRecorder struct {
+	mock *MockOfferUpdateRepository
+}
+
+// NewMockOfferUpdateRepository creates a new mock instance.
+func NewMockOfferUpdateRepository(ctrl *gomock.Controller) *MockOfferUpdateRepository {
+	mock := &MockOfferUpdateRepository{ctrl: ctrl}
+	mock.recorder = &MockOfferUpdateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferUpdateRepository) EXPECT() *MockOfferUpdateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Listen mocks base method.
+func (m *MockOfferUpdateRepository) Listen(ctx context.Context, handle func([]byte)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Listen", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Listen indicates an expected call of Listen.
+func (mr *MockOfferUpdateRepositoryMockRecorder) Listen(ctx, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Listen", reflect.TypeOf((*MockOfferUpdateRepository)(nil).Listen), ctx, handle)
+}
+
+// Publish mocks base method.
+func (m *MockOfferUpdateRepository) Publish(ctx context.Context, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockOfferUpdateRepositoryMockRecorder) Publish(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockOfferUpdateRepository)(nil).Publish), ctx, payload)
+}
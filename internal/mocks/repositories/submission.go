@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/submission.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "financing-aggregator/internal/models"
+	repositories "financing-aggregator/internal/repositories"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSubmissionRepository is a mock of SubmissionRepository interface.
+type MockSubmissionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubmissionRepositoryMockRecorder
+}
+
+// MockSubmissionRepositoryMockRecorder is the mock recorder for MockSubmissionRepository.
+type MockSubmissionRepositoryMockRecorder struct {
+	mock *MockSubmissionRepository
+}
+
+// NewMockSubmissionRepository creates a new mock instance.
+func NewMockSubmissionRepository(ctrl *gomock.Controller) *MockSubmissionRepository {
+	mock := &MockSubmissionRepository{ctrl: ctrl}
+	mock.recorder = &MockSubmissionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubmissionRepository) EXPECT() *MockSubmissionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockSubmissionRepository) ClaimDue(ctx context.Context, lease repositories.Lease) ([]models.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, lease)
+	ret0, _ := ret[0].([]models.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockSubmissionRepositoryMockRecorder) ClaimDue(ctx, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockSubmissionRepository)(nil).ClaimDue), ctx, lease)
+}
+
+// Complete mocks base method.
+func (m *MockSubmissionRepository) Complete(ctx context.Context, submission models.Submission, offer *models.Offer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, submission, offer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockSubmissionRepositoryMockRecorder) Complete(ctx, submission, offer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockSubmissionRepository)(nil).Complete), ctx, submission, offer)
+}
+
+// Update mocks base method.
+func (m *MockSubmissionRepository) Update(ctx context.Context, submission models.Submission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, submission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubmissionRepositoryMockRecorder) Update(ctx, submission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubmissionRepository)(nil).Update), ctx, submission)
+}
@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/merchant.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	auth "financing-aggregator/internal/auth"
+	dto "financing-aggregator/internal/dto"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMerchantService is a mock of MerchantService interface.
+type MockMerchantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchantServiceMockRecorder
+}
+
+// MockMerchantServiceMockRecorder is the mock recorder for MockMerchantService.
+type MockMerchantServiceMockRecorder struct {
+	mock *MockMerchantService
+}
+
+// NewMockMerchantService creates a new mock instance.
+func NewMockMerchantService(ctrl *gomock.Controller) *MockMerchantService {
+	mock := &MockMerchantService{ctrl: ctrl}
+	mock.recorder = &MockMerchantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchantService) EXPECT() *MockMerchantServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockMerchantService) Authenticate(ctx context.Context, key string) (auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockMerchantServiceMockRecorder) Authenticate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockMerchantService)(nil).Authenticate), ctx, key)
+}
+
+// ConsumeApplicationQuota mocks base method.
+func (m *MockMerchantService) ConsumeApplicationQuota(ctx context.Context, keyID string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeApplicationQuota", ctx, keyID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeApplicationQuota indicates an expected call of ConsumeApplicationQuota.
+func (mr *MockMerchantServiceMockRecorder) ConsumeApplicationQuota(ctx, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeApplicationQuota", reflect.TypeOf((*MockMerchantService)(nil).ConsumeApplicationQuota), ctx, keyID)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockMerchantService) CreateAPIKey(ctx context.Context, merchantID string, requestQuota, applicationQuota int) (dto.APIKeyDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, merchantID, requestQuota, applicationQuota)
+	ret0, _ := ret[0].(dto.APIKeyDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockMerchantServiceMockRecorder) CreateAPIKey(ctx, merchantID, requestQuota, applicationQuota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockMerchantService)(nil).CreateAPIKey), ctx, merchantID, requestQuota, applicationQuota)
+}
+
+// CreateMerchant mocks base method.
+func (m *MockMerchantService) CreateMerchant(ctx context.Context, name string) (dto.MerchantDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMerchant", ctx, name)
+	ret0, _ := ret[0].(dto.MerchantDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMerchant indicates an expected call of CreateMerchant.
+func (mr *MockMerchantServiceMockRecorder) CreateMerchant(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMerchant", reflect.TypeOf((*MockMerchantService)(nil).CreateMerchant), ctx, name)
+}
+
+// GetMerchant mocks base method.
+func (m *MockMerchantService) GetMerchant(ctx context.Context, id string) (dto.MerchantDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchant", ctx, id)
+	ret0, _ := ret[0].(dto.MerchantDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchant indicates an expected call of GetMerchant.
+func (mr *MockMerchantServiceMockRecorder) GetMerchant(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchant", reflect.TypeOf((*MockMerchantService)(nil).GetMerchant), ctx, id)
+}
+
+// PurgeQuotaUsage mocks base method.
+func (m *MockMerchantService) PurgeQuotaUsage(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PurgeQuotaUsage", ctx)
+}
+
+// PurgeQuotaUsage indicates an expected call of PurgeQuotaUsage.
+func (mr *MockMerchantServiceMockRecorder) PurgeQuotaUsage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeQuotaUsage", reflect.TypeOf((*MockMerchantService)(nil).PurgeQuotaUsage), ctx)
+}
+
+// RefundApplicationQuota mocks base method.
+func (m *MockMerchantService) RefundApplicationQuota(ctx context.Context, keyID string, windowStart time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RefundApplicationQuota", ctx, keyID, windowStart)
+}
+
+// RefundApplicationQuota indicates an expected call of RefundApplicationQuota.
+func (mr *MockMerchantServiceMockRecorder) RefundApplicationQuota(ctx, keyID, windowStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundApplicationQuota", reflect.TypeOf((*MockMerchantService)(nil).RefundApplicationQuota), ctx, keyID, windowStart)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockMerchantService) RevokeAPIKey(ctx context.Context, merchantID, keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, merchantID, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockMerchantServiceMockRecorder) RevokeAPIKey(ctx, merchantID, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockMerchantService)(nil).RevokeAPIKey), ctx, merchantID, keyID)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockMerchantService) UpdateSettings(ctx context.Context, id string, settings dto.MerchantSettingsDTO) (dto.MerchantDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx, id, settings)
+	ret0, _ := ret[0].(dto.MerchantDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockMerchantServiceMockRecorder) UpdateSettings(ctx, id, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockMerchantService)(nil).UpdateSettings), ctx, id, settings)
+}
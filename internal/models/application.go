@@ -1,12 +1,16 @@
 package models
 
 import (
+	"financing-aggregator/internal/money"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"time"
 )
 
 const (
+	// DefaultCurrency is the currency of applications submitted without one.
+	DefaultCurrency string = "EUR"
+
 	MaritalStatusSingle     string = "SINGLE"
 	MaritalStatusMarried    string = "MARRIED"
 	MaritalStatusDivorced   string = "DIVORCED"
@@ -15,6 +19,7 @@ const (
 	OfferStatusDraft     string = "DRAFT"
 	OfferStatusProcessed string = "PROCESSED"
 	OfferStatusDeclined  string = "DECLINED"
+	OfferStatusTimedOut  string = "TIMED_OUT"
 )
 
 type Application struct {
@@ -23,17 +28,25 @@ type Application struct {
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt"`
 
-	Phone                    string  `json:"phone"`
-	Email                    string  `json:"email"`
-	MonthlyIncome            float64 `json:"monthlyIncome"`
-	MonthlyExpenses          float64 `json:"monthlyExpenses"`
-	MonthlyCreditLiabilities float64 `json:"monthlyCreditLiabilities"`
-	MaritalStatus            string  `gorm:"type:marital_status_enum" json:"maritalStatus"`
-	Dependents               int     `json:"dependents"`
-	AgreeToDataSharing       bool    `json:"agreeToDataSharing"`
-	AgreeToBeScored          bool    `json:"agreeToBeScored"`
-	Amount                   float64 `json:"amount"`
-	Offers                   []Offer `gorm:"foreignKey:ApplicationID" json:"offers"`
+	// UserID references the user who submitted the application, nil for anonymous submissions.
+	UserID *uuid.UUID `gorm:"type:uuid" json:"userId"`
+	// MerchantID references the merchant whose API key the application was submitted with.
+	MerchantID *uuid.UUID `gorm:"type:uuid" json:"merchantId"`
+
+	Phone                    string       `json:"phone"`
+	Email                    string       `json:"email"`
+	MonthlyIncome            money.Amount `json:"monthlyIncome"`
+	MonthlyExpenses          money.Amount `json:"monthlyExpenses"`
+	MonthlyCreditLiabilities money.Amount `json:"monthlyCreditLiabilities"`
+	MaritalStatus            string       `gorm:"type:marital_status_enum" json:"maritalStatus"`
+	Dependents               int          `json:"dependents"`
+	AgreeToDataSharing       bool         `json:"agreeToDataSharing"`
+	AgreeToBeScored          bool         `json:"agreeToBeScored"`
+	Amount                   money.Amount `json:"amount"`
+	Currency                 string       `json:"currency"`
+	Offers                   []Offer      `gorm:"foreignKey:ApplicationID" json:"offers"`
+	Submissions              []Submission `gorm:"foreignKey:ApplicationID" json:"-"`
+	Events                   []OfferEvent `gorm:"foreignKey:ApplicationID" json:"-"`
 }
 
 func (a *Application) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the response to a request sent with an Idempotency-Key header, so
+// that retries of the request are answered with it instead of being processed again. Keys
+// are chosen by callers and unique per subject of the caller.
+type IdempotencyKey struct {
+	Subject   string    `gorm:"primaryKey" json:"subject"`
+	Key       string    `gorm:"primaryKey" json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	RequestHash string `json:"requestHash"`
+	// StatusCode is 0 while the request is being processed.
+	StatusCode   int    `json:"statusCode"`
+	ResponseBody []byte `json:"-"`
+}
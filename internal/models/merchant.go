@@ -0,0 +1,81 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	QuotaKindRequests     string = "REQUESTS"
+	QuotaKindApplications string = "APPLICATIONS"
+)
+
+// Merchant is a checkout integrating the aggregator with API keys. Every merchant is a
+// tenant with its own applications, banks and branding.
+type Merchant struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt"`
+
+	Name string `json:"name"`
+	// Banks lists banks applications of the merchant are submitted to, all configured banks
+	// if empty.
+	Banks    []MerchantBank   `gorm:"serializer:json" json:"banks"`
+	Branding MerchantBranding `gorm:"serializer:json" json:"branding"`
+	APIKeys  []MerchantAPIKey `gorm:"foreignKey:MerchantID" json:"-"`
+}
+
+func (m *Merchant) BeforeCreate(tx *gorm.DB) (err error) {
+	m.ID = uuid.New()
+	return
+}
+
+// MerchantBank enables a configured bank for a merchant. BaseURL and Headers override the
+// bank config for the merchant, e.g. with its own credentials.
+type MerchantBank struct {
+	Name    string            `json:"name"`
+	BaseURL string            `json:"baseUrl,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// MerchantBranding is shown by clients of the merchant.
+type MerchantBranding struct {
+	DisplayName  string `json:"displayName,omitempty"`
+	LogoURL      string `json:"logoUrl,omitempty"`
+	PrimaryColor string `json:"primaryColor,omitempty"`
+}
+
+// MerchantAPIKey is a key a merchant authenticates with. Only the SHA-256 hash of the key is
+// stored, the prefix identifies it for support. Quotas limit requests and applications per
+// configured window, 0 means no limit.
+type MerchantAPIKey struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	MerchantID       uuid.UUID  `json:"merchantId"`
+	Prefix           string     `json:"prefix"`
+	KeyHash          string     `json:"-"`
+	RequestQuota     int        `json:"requestQuota"`
+	ApplicationQuota int        `json:"applicationQuota"`
+	RevokedAt        *time.Time `json:"revokedAt"`
+}
+
+func (k *MerchantAPIKey) BeforeCreate(tx *gorm.DB) (err error) {
+	k.ID = uuid.New()
+	return
+}
+
+// MerchantAPIKeyUsage counts requests or applications of an API key in a quota window.
+type MerchantAPIKeyUsage struct {
+	APIKeyID    uuid.UUID `gorm:"primaryKey" json:"apiKeyId"`
+	Kind        string    `gorm:"primaryKey" json:"kind"`
+	WindowStart time.Time `gorm:"primaryKey" json:"windowStart"`
+	Count       int       `json:"count"`
+}
+
+func (MerchantAPIKeyUsage) TableName() string {
+	return "merchant_api_key_usage"
+}
@@ -1,26 +1,44 @@
 package models
 
 import (
+	"financing-aggregator/internal/money"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"time"
 )
 
+const (
+	OfferVerificationVerified string = "VERIFIED"
+	OfferVerificationMismatch string = "MISMATCH"
+)
+
 type Offer struct {
 	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt"`
 
-	ApplicationID        uuid.UUID `json:"applicationId"`
-	ExternalID           string    `json:"externalId"`
-	Bank                 string    `json:"bank"`
-	Status               string    `gorm:"type:application_status_enum" json:"status"`
-	MonthlyPaymentAmount float64   `json:"monthlyPaymentAmount"`
-	TotalRepaymentAmount float64   `json:"totalRepaymentAmount"`
-	NumberOfPayments     int       `json:"numberOfPayments"`
-	AnnualPercentageRate float64   `json:"annualPercentageRate"`
-	FirstRepaymentDate   time.Time `json:"firstRepaymentDate"`
+	ApplicationID        uuid.UUID    `json:"applicationId"`
+	MerchantID           *uuid.UUID   `json:"-"`
+	ExternalID           string       `json:"externalId"`
+	Bank                 string       `json:"bank"`
+	Status               string       `gorm:"type:application_status_enum" json:"status"`
+	MonthlyPaymentAmount money.Amount `json:"monthlyPaymentAmount"`
+	TotalRepaymentAmount money.Amount `json:"totalRepaymentAmount"`
+	Currency             string       `json:"currency"`
+	NumberOfPayments     int          `json:"numberOfPayments"`
+	AnnualPercentageRate float64      `json:"annualPercentageRate"`
+	FirstRepaymentDate   time.Time    `json:"firstRepaymentDate"`
+	NextPollAt           time.Time    `json:"-"`
+	PollAttempts         int          `json:"-"`
+	LockedBy             *string      `json:"-"`
+	LockedUntil          *time.Time   `json:"-"`
+
+	// Computed figures are recomputed from the payment schedule to verify the stated ones.
+	ComputedAnnualPercentageRate float64      `json:"computedAnnualPercentageRate"`
+	ComputedTotalRepaymentAmount money.Amount `json:"computedTotalRepaymentAmount"`
+	Verification                 string       `json:"verification"`
+	Quarantined                  bool         `json:"quarantined"`
 }
 
 func (o *Offer) BeforeCreate(tx *gorm.DB) (err error) {
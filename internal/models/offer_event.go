@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	OfferEventSubmitted          string = "SUBMITTED"
+	OfferEventSubmissionFailed   string = "SUBMISSION_FAILED"
+	OfferEventBankResponse       string = "BANK_RESPONSE"
+	OfferEventStatusChanged      string = "STATUS_CHANGED"
+	OfferEventTermsChanged       string = "TERMS_CHANGED"
+	OfferEventBroadcast          string = "BROADCAST"
+	OfferEventVerificationFailed string = "VERIFICATION_FAILED"
+)
+
+// OfferEvent records a single step of processing an application by banks. Events are
+// never updated, so together they form the application timeline.
+type OfferEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	ApplicationID uuid.UUID  `json:"applicationId"`
+	OfferID       *uuid.UUID `json:"offerId"`
+	Bank          string     `json:"bank"`
+	Type          string     `gorm:"type:offer_event_type_enum" json:"type"`
+	Status        string     `json:"status"`
+	Details       string     `json:"details"`
+}
+
+func (e *OfferEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.New()
+	return
+}
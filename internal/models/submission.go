@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	SubmissionStatusPending   string = "PENDING"
+	SubmissionStatusSubmitted string = "SUBMITTED"
+	SubmissionStatusFailed    string = "FAILED"
+)
+
+// Submission tracks delivery of an application to a single bank.
+type Submission struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt"`
+
+	ApplicationID uuid.UUID   `json:"applicationId"`
+	Application   Application `gorm:"foreignKey:ApplicationID" json:"-"`
+	Bank          string      `json:"bank"`
+	Status        string      `gorm:"type:submission_status_enum" json:"status"`
+	Attempts      int         `json:"attempts"`
+	LastError     string      `json:"lastError"`
+	NextAttemptAt time.Time   `json:"nextAttemptAt"`
+	LockedBy      *string     `json:"-"`
+	LockedUntil   *time.Time  `json:"-"`
+}
+
+func (s *Submission) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	return
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"financing-aggregator/internal/money"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// User is a registered customer. Profile fields are defaults of applications the user
+// submits without them.
+type User struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt"`
+
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+
+	Phone                    string       `json:"phone"`
+	MonthlyIncome            money.Amount `json:"monthlyIncome"`
+	MonthlyExpenses          money.Amount `json:"monthlyExpenses"`
+	MonthlyCreditLiabilities money.Amount `json:"monthlyCreditLiabilities"`
+	MaritalStatus            string       `json:"maritalStatus"`
+	Dependents               int          `json:"dependents"`
+}
+
+func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
+	u.ID = uuid.New()
+	return
+}
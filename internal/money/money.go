@@ -0,0 +1,151 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrPrecision     = errors.New("amount has more than 2 decimal places")
+)
+
+// Amount is an exact monetary amount in cents. It is encoded as a JSON number and
+// stored as NUMERIC, so values never pass through binary floating point.
+type Amount int64
+
+// FromFloat returns the amount closest to f.
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * 100))
+}
+
+// Parse reads a decimal amount such as "1234.56". Amounts with more than 2 decimal
+// places are rejected with ErrPrecision.
+func Parse(s string) (Amount, error) {
+	cents, err := parseCents(s)
+	if err != nil {
+		return 0, err
+	}
+	if !cents.IsInt() {
+		return 0, errors.Wrap(ErrPrecision, s)
+	}
+	return fromInt(cents.Num(), s)
+}
+
+// ParseRounded reads a decimal amount like Parse, rounding it half away from zero to cents.
+func ParseRounded(s string) (Amount, error) {
+	cents, err := parseCents(s)
+	if err != nil {
+		return 0, err
+	}
+
+	// Add half a cent away from zero and truncate towards zero.
+	cents.Add(cents, big.NewRat(int64(cents.Sign()), 2))
+	return fromInt(new(big.Int).Quo(cents.Num(), cents.Denom()), s)
+}
+
+func parseCents(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidAmount, s)
+	}
+	return r.Mul(r, big.NewRat(100, 1)), nil
+}
+
+func fromInt(cents *big.Int, s string) (Amount, error) {
+	if !cents.IsInt64() {
+		return 0, errors.Wrap(ErrInvalidAmount, s)
+	}
+	return Amount(cents.Int64()), nil
+}
+
+// MustParse is like Parse but panics on invalid amounts.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Mul returns the amount multiplied by factor and rounded to cents.
+func (a Amount) Mul(factor float64) Amount {
+	return Amount(math.Round(float64(a) * factor))
+}
+
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Float64 returns the amount in currency units, for calculations that are not exact anyway.
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+// String formats the amount with 2 decimal places, e.g. "1234.50".
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+	}
+	abs := uint64(a.Abs())
+	if a == math.MinInt64 {
+		abs = uint64(math.MaxInt64) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and strings holding a decimal amount.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	amount, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func (a *Amount) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v * 100)
+	case float64:
+		*a = FromFloat(v)
+	case []byte:
+		*a, err = Parse(string(v))
+	case string:
+		*a, err = Parse(v)
+	default:
+		err = fmt.Errorf("cannot scan %T into amount", src)
+	}
+	return err
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
@@ -0,0 +1,96 @@
+package money
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type moneyTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(moneyTestSuite))
+}
+
+func (s *moneyTestSuite) Test_Parse() {
+	s.Run("decimal amounts parsed exactly", func() {
+		for input, expected := range map[string]Amount{
+			"1234.56": 123456,
+			"0.1":     10,
+			"-5":      -500,
+			"1e3":     100000,
+			"0.30":    30,
+		} {
+			actual, err := Parse(input)
+			s.NoError(err)
+			s.Equal(expected, actual, input)
+		}
+	})
+
+	s.Run("error occurs because amount has sub-cent precision", func() {
+		_, err := Parse("10.005")
+		s.ErrorIs(err, ErrPrecision)
+	})
+
+	s.Run("error occurs because amount is invalid", func() {
+		_, err := Parse("ten")
+		s.ErrorIs(err, ErrInvalidAmount)
+	})
+}
+
+func (s *moneyTestSuite) Test_ParseRounded() {
+	for input, expected := range map[string]Amount{
+		"10.004":  1000,
+		"10.005":  1001,
+		"-10.005": -1001,
+		"88.85":   8885,
+	} {
+		actual, err := ParseRounded(input)
+		s.NoError(err)
+		s.Equal(expected, actual, input)
+	}
+}
+
+func (s *moneyTestSuite) Test_String() {
+	s.Equal("1234.56", Amount(123456).String())
+	s.Equal("0.05", Amount(5).String())
+	s.Equal("-1.50", Amount(-150).String())
+}
+
+func (s *moneyTestSuite) Test_JSON() {
+	var actual struct {
+		Number Amount `json:"number"`
+		String Amount `json:"string"`
+	}
+
+	s.Run("numbers and strings decoded without rounding artifacts", func() {
+		s.NoError(json.Unmarshal([]byte(`{"number": 1234.56, "string": "0.1"}`), &actual))
+		s.Equal(Amount(123456), actual.Number)
+		s.Equal(Amount(10), actual.String)
+	})
+
+	s.Run("amounts encoded as numbers", func() {
+		data, err := json.Marshal(actual)
+		s.NoError(err)
+		s.JSONEq(`{"number": 1234.56, "string": 0.10}`, string(data))
+	})
+
+	s.Run("error occurs because amount has sub-cent precision", func() {
+		s.Error(json.Unmarshal([]byte(`{"number": 0.001}`), &actual))
+	})
+}
+
+func (s *moneyTestSuite) Test_Scan() {
+	var actual Amount
+	s.NoError(actual.Scan([]byte("1234.56")))
+	s.Equal(Amount(123456), actual)
+
+	value, err := actual.Value()
+	s.NoError(err)
+	s.Equal("1234.56", value)
+
+	s.NoError(actual.Scan(int64(7)))
+	s.Equal(Amount(700), actual)
+}
@@ -0,0 +1,85 @@
+package ranking
+
+import (
+	"financing-aggregator/internal/dto"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CriterionAPR            = "apr"
+	CriterionTotalRepayment = "totalRepayment"
+	CriterionMonthlyPayment = "monthlyPayment"
+)
+
+var ErrUnknownCriterion = errors.New("unknown ranking criterion")
+
+// criteria holds the compared value of every criterion, lower values are better.
+var criteria = map[string]func(dto.OfferDTO) float64{
+	CriterionAPR:            func(o dto.OfferDTO) float64 { return o.AnnualPercentageRate },
+	CriterionTotalRepayment: func(o dto.OfferDTO) float64 { return o.TotalRepaymentAmount.Float64() },
+	CriterionMonthlyPayment: func(o dto.OfferDTO) float64 { return o.MonthlyPaymentAmount.Float64() },
+}
+
+// Ranker orders offers from best to worst. Offers are compared by the configured criteria
+// in order, every next criterion breaking ties of the previous ones.
+type Ranker struct {
+	criteria []string
+}
+
+func New(rankCriteria []string) (*Ranker, error) {
+	if len(rankCriteria) == 0 {
+		rankCriteria = []string{CriterionAPR, CriterionTotalRepayment, CriterionMonthlyPayment}
+	}
+	for _, c := range rankCriteria {
+		if _, ok := criteria[c]; !ok {
+			return nil, errors.Wrap(ErrUnknownCriterion, c)
+		}
+	}
+	return &Ranker{criteria: rankCriteria}, nil
+}
+
+// Rank marks the best offer as recommended and returns offers sorted by sortBy, followed
+// by the configured criteria. Offers are sorted by the configured criteria only if sortBy
+// is empty.
+func (r *Ranker) Rank(offers []dto.OfferDTO, sortBy string) ([]dto.OfferDTO, error) {
+	order := r.criteria
+	if sortBy != "" {
+		if _, ok := criteria[sortBy]; !ok {
+			return nil, errors.Wrap(ErrUnknownCriterion, sortBy)
+		}
+		order = append([]string{sortBy}, r.criteria...)
+	}
+
+	ranked := slices.Clone(offers)
+	compareBest := r.compareFunc(r.criteria)
+	best := 0
+	for i := range ranked {
+		ranked[i].Recommended = false
+		if compareBest(ranked[i], ranked[best]) < 0 {
+			best = i
+		}
+	}
+	if len(ranked) > 0 {
+		ranked[best].Recommended = true
+	}
+
+	slices.SortStableFunc(ranked, r.compareFunc(order))
+	return ranked, nil
+}
+
+func (r *Ranker) compareFunc(order []string) func(a, b dto.OfferDTO) int {
+	return func(a, b dto.OfferDTO) int {
+		for _, c := range order {
+			value := criteria[c]
+			if diff := value(a) - value(b); diff != 0 {
+				if diff < 0 {
+					return -1
+				}
+				return 1
+			}
+		}
+		return 0
+	}
+}
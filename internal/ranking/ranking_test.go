@@ -0,0 +1,96 @@
+package ranking
+
+import (
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type rankingTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(rankingTestSuite))
+}
+
+func (s *rankingTestSuite) Test_New() {
+	s.Run("default criteria used", func() {
+		ranker, err := New(nil)
+		s.NoError(err)
+		s.Equal([]string{CriterionAPR, CriterionTotalRepayment, CriterionMonthlyPayment}, ranker.criteria)
+	})
+
+	s.Run("error occurs because criterion is unknown", func() {
+		_, err := New([]string{CriterionAPR, "color"})
+		s.ErrorIs(err, ErrUnknownCriterion)
+	})
+}
+
+func (s *rankingTestSuite) Test_Rank() {
+	offers := []dto.OfferDTO{
+		getTestOffer("bank1", 12, 1200, 100),
+		getTestOffer("bank2", 10, 1300, 50),
+		getTestOffer("bank3", 10, 1250, 60),
+	}
+	ranker, err := New([]string{CriterionAPR, CriterionTotalRepayment})
+	s.Require().NoError(err)
+
+	s.Run("offers ranked by configured criteria", func() {
+		actual, err := ranker.Rank(offers, "")
+		s.NoError(err)
+		s.Equal([]string{"bank3", "bank2", "bank1"}, banks(actual))
+		s.Equal([]bool{true, false, false}, recommended(actual))
+	})
+
+	s.Run("offers sorted by requested criterion", func() {
+		actual, err := ranker.Rank(offers, CriterionMonthlyPayment)
+		s.NoError(err)
+		s.Equal([]string{"bank2", "bank3", "bank1"}, banks(actual))
+		s.Equal([]bool{false, true, false}, recommended(actual))
+	})
+
+	s.Run("ties broken by configured criteria", func() {
+		actual, err := ranker.Rank(offers, CriterionAPR)
+		s.NoError(err)
+		s.Equal([]string{"bank3", "bank2", "bank1"}, banks(actual))
+	})
+
+	s.Run("no offers", func() {
+		actual, err := ranker.Rank(nil, "")
+		s.NoError(err)
+		s.Empty(actual)
+	})
+
+	s.Run("error occurs because sort criterion is unknown", func() {
+		_, err := ranker.Rank(offers, "color")
+		s.ErrorIs(err, ErrUnknownCriterion)
+	})
+}
+
+func getTestOffer(bank string, apr, total, monthly float64) dto.OfferDTO {
+	return dto.OfferDTO{
+		Bank:                 bank,
+		Status:               "PROCESSED",
+		AnnualPercentageRate: apr,
+		TotalRepaymentAmount: money.FromFloat(total),
+		MonthlyPaymentAmount: money.FromFloat(monthly),
+	}
+}
+
+func banks(offers []dto.OfferDTO) []string {
+	names := make([]string, 0, len(offers))
+	for _, o := range offers {
+		names = append(names, o.Bank)
+	}
+	return names
+}
+
+func recommended(offers []dto.OfferDTO) []bool {
+	flags := make([]bool, 0, len(offers))
+	for _, o := range offers {
+		flags = append(flags, o.Recommended)
+	}
+	return flags
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"financing-aggregator/internal/models"
 	"gorm.io/gorm"
+	"slices"
+	"strings"
+	"time"
 )
 
 type ApplicationRepository interface {
 	Create(ctx context.Context, app *models.Application) error
+	CreateUnlessDuplicate(ctx context.Context, app *models.Application, since time.Time) (*models.Application, error)
+	Get(ctx context.Context, id string) (models.Application, error)
 	GetWithProcessedOffers(ctx context.Context, id string) (models.Application, error)
 }
 
@@ -23,9 +28,75 @@ func (r *applicationRepository) Create(ctx context.Context, app *models.Applicat
 	return r.db.WithContext(ctx).Create(app).Error
 }
 
+// CreateUnlessDuplicate creates the application unless an application of the same merchant
+// and user with the same email or phone and the same financial details was created since the
+// given time, in which case that application is returned with its processed offers instead.
+// Concurrent calls for the same email or phone are serialized with advisory locks, so double
+// submits are detected too.
+func (r *applicationRepository) CreateUnlessDuplicate(ctx context.Context, app *models.Application, since time.Time) (*models.Application, error) {
+	var duplicate *models.Application
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Empty contacts are optional fields left out, which do not identify the applicant.
+		var contacts, lockKeys []string
+		var contactArgs []any
+		if app.Email != "" {
+			contacts = append(contacts, "email = ?")
+			contactArgs = append(contactArgs, app.Email)
+			lockKeys = append(lockKeys, "application:email:"+app.Email)
+		}
+		if app.Phone != "" {
+			contacts = append(contacts, "phone = ?")
+			contactArgs = append(contactArgs, app.Phone)
+			lockKeys = append(lockKeys, "application:phone:"+app.Phone)
+		}
+		if len(lockKeys) == 0 {
+			return tx.Create(app).Error
+		}
+
+		// Locks are taken in a fixed order, so that concurrent calls cannot deadlock.
+		slices.Sort(lockKeys)
+		for _, key := range lockKeys {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+				return err
+			}
+		}
+
+		var existing []models.Application
+		err := tx.Preload("Offers", "status = ? AND NOT quarantined", "PROCESSED").
+			Where(strings.Join(contacts, " OR "), contactArgs...).
+			Where("created_at >= ?", since).
+			Where("merchant_id IS NOT DISTINCT FROM ? AND user_id IS NOT DISTINCT FROM ?", app.MerchantID, app.UserID).
+			Where("amount = ? AND currency = ? AND monthly_income = ? AND monthly_expenses = ? AND monthly_credit_liabilities = ?",
+				app.Amount, app.Currency, app.MonthlyIncome, app.MonthlyExpenses, app.MonthlyCreditLiabilities).
+			Where("marital_status = ? AND dependents = ?", app.MaritalStatus, app.Dependents).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&existing).Error
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			duplicate = &existing[0]
+			return nil
+		}
+
+		return tx.Create(app).Error
+	})
+	return duplicate, err
+}
+
+func (r *applicationRepository) Get(ctx context.Context, id string) (models.Application, error) {
+	var app models.Application
+	err := r.db.WithContext(ctx).First(&app, "id = ?", id).Error
+	if err != nil {
+		return models.Application{}, err
+	}
+	return app, nil
+}
+
 func (r *applicationRepository) GetWithProcessedOffers(ctx context.Context, id string) (models.Application, error) {
 	var app models.Application
-	err := r.db.WithContext(ctx).Preload("Offers", "status = ?", "PROCESSED").First(&app, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("Offers", "status = ? AND NOT quarantined", "PROCESSED").First(&app, "id = ?", id).Error
 	if err != nil {
 		return models.Application{}, err
 	}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"financing-aggregator/internal/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+type IdempotencyKeyRepository interface {
+	Acquire(ctx context.Context, subject, key, requestHash string, lockedBefore, expiredBefore time.Time) (bool, error)
+	Get(ctx context.Context, subject, key string) (models.IdempotencyKey, error)
+	Complete(ctx context.Context, subject, key string, statusCode int, responseBody []byte) error
+	Delete(ctx context.Context, subject, key string) error
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Acquire reserves the key of the subject for a request with the given hash and reports
+// whether it was reserved. Keys of requests still in progress since lockedBefore, as abandoned by crashed
+// replicas, and keys created before expiredBefore are reserved again.
+func (r *idempotencyKeyRepository) Acquire(ctx context.Context, subject, key, requestHash string, lockedBefore, expiredBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO idempotency_keys (subject, key, request_hash, status_code, created_at, updated_at)
+		VALUES (?, ?, ?, 0, NOW(), NOW())
+		ON CONFLICT (subject, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = 0, response_body = NULL,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at
+		WHERE (idempotency_keys.status_code = 0 AND idempotency_keys.updated_at < ?)
+			OR idempotency_keys.created_at < ?`,
+		subject, key, requestHash, lockedBefore, expiredBefore)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *idempotencyKeyRepository) Get(ctx context.Context, subject, key string) (models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+	err := r.db.WithContext(ctx).First(&idempotencyKey, "subject = ? AND key = ?", subject, key).Error
+	if err != nil {
+		return models.IdempotencyKey{}, err
+	}
+	return idempotencyKey, nil
+}
+
+// Complete stores the response to the request holding the key.
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, subject, key string, statusCode int, responseBody []byte) error {
+	return r.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("subject = ? AND key = ?", subject, key).
+		Updates(map[string]any{"status_code": statusCode, "response_body": responseBody}).Error
+}
+
+func (r *idempotencyKeyRepository) Delete(ctx context.Context, subject, key string) error {
+	return r.db.WithContext(ctx).Delete(&models.IdempotencyKey{}, "subject = ? AND key = ?", subject, key).Error
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"financing-aggregator/internal/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+type MerchantRepository interface {
+	Create(ctx context.Context, merchant *models.Merchant) error
+	Get(ctx context.Context, id string) (models.Merchant, error)
+	UpdateSettings(ctx context.Context, merchant *models.Merchant) error
+	CreateAPIKey(ctx context.Context, key *models.MerchantAPIKey) error
+	GetAPIKey(ctx context.Context, id string) (models.MerchantAPIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (models.MerchantAPIKey, error)
+	RevokeAPIKey(ctx context.Context, merchantID, keyID string) (bool, error)
+	IncrementUsage(ctx context.Context, keyID, kind string, windowStart time.Time, limit int) (bool, error)
+	DecrementUsage(ctx context.Context, keyID, kind string, windowStart time.Time) error
+	DeleteUsageBefore(ctx context.Context, windowStart time.Time) (int64, error)
+}
+
+type merchantRepository struct {
+	db *gorm.DB
+}
+
+func NewMerchantRepository(db *gorm.DB) MerchantRepository {
+	return &merchantRepository{db: db}
+}
+
+func (r *merchantRepository) Create(ctx context.Context, merchant *models.Merchant) error {
+	return r.db.WithContext(ctx).Create(merchant).Error
+}
+
+func (r *merchantRepository) Get(ctx context.Context, id string) (models.Merchant, error) {
+	var merchant models.Merchant
+	err := r.db.WithContext(ctx).First(&merchant, "id = ?", id).Error
+	if err != nil {
+		return models.Merchant{}, err
+	}
+	return merchant, nil
+}
+
+// UpdateSettings stores banks and branding of the merchant.
+func (r *merchantRepository) UpdateSettings(ctx context.Context, merchant *models.Merchant) error {
+	return r.db.WithContext(ctx).Model(merchant).Select("banks", "branding").Updates(merchant).Error
+}
+
+func (r *merchantRepository) CreateAPIKey(ctx context.Context, key *models.MerchantAPIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *merchantRepository) GetAPIKey(ctx context.Context, id string) (models.MerchantAPIKey, error) {
+	var key models.MerchantAPIKey
+	err := r.db.WithContext(ctx).First(&key, "id = ?", id).Error
+	if err != nil {
+		return models.MerchantAPIKey{}, err
+	}
+	return key, nil
+}
+
+// GetAPIKeyByHash returns the unrevoked key with the hash.
+func (r *merchantRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (models.MerchantAPIKey, error) {
+	var key models.MerchantAPIKey
+	err := r.db.WithContext(ctx).First(&key, "key_hash = ? AND revoked_at IS NULL", keyHash).Error
+	if err != nil {
+		return models.MerchantAPIKey{}, err
+	}
+	return key, nil
+}
+
+// RevokeAPIKey revokes the key of the merchant and reports whether an unrevoked key was found.
+func (r *merchantRepository) RevokeAPIKey(ctx context.Context, merchantID, keyID string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.MerchantAPIKey{}).
+		Where("id = ? AND merchant_id = ? AND revoked_at IS NULL", keyID, merchantID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// IncrementUsage counts a use of the key in the window and reports whether it was counted,
+// which it is not once the count reached the limit.
+func (r *merchantRepository) IncrementUsage(ctx context.Context, keyID, kind string, windowStart time.Time, limit int) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO merchant_api_key_usage (api_key_id, kind, window_start, count)
+		VALUES (?, ?, ?, 1)
+		ON CONFLICT (api_key_id, kind, window_start) DO UPDATE
+		SET count = merchant_api_key_usage.count + 1
+		WHERE merchant_api_key_usage.count < ?`,
+		keyID, kind, windowStart, limit)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DecrementUsage takes back a use of the key counted in the window.
+func (r *merchantRepository) DecrementUsage(ctx context.Context, keyID, kind string, windowStart time.Time) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE merchant_api_key_usage SET count = count - 1
+		WHERE api_key_id = ? AND kind = ? AND window_start = ? AND count > 0`,
+		keyID, kind, windowStart).Error
+}
+
+// DeleteUsageBefore deletes counts of windows started before the given time.
+func (r *merchantRepository) DeleteUsageBefore(ctx context.Context, windowStart time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("window_start < ?", windowStart).Delete(&models.MerchantAPIKeyUsage{})
+	return result.RowsAffected, result.Error
+}
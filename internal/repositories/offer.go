@@ -4,16 +4,22 @@ import (
 	"context"
 	"financing-aggregator/internal/models"
 	"gorm.io/gorm"
+	"time"
 )
 
 type OfferRepository interface {
 	Create(ctx context.Context, offer *models.Offer) error
-	List(ctx context.Context, filter OfferListFilter) ([]models.Offer, error)
-	Update(ctx context.Context, id string, offer models.Offer) error
+	Claim(ctx context.Context, filter OfferListFilter, lease Lease) ([]models.Offer, error)
+	Reschedule(ctx context.Context, id string, pollAttempts int, nextPollAt time.Time) error
+	TimeOut(ctx context.Context, bank string, createdBefore time.Time) ([]models.Offer, error)
+	ListByExternalID(ctx context.Context, bank, externalID string) ([]models.Offer, error)
+	GetByApplication(ctx context.Context, applicationID, id string) (models.Offer, error)
+	UpdateDraft(ctx context.Context, id string, offer models.Offer) (bool, error)
 }
 
 type OfferListFilter struct {
-	Status string
+	Status       string
+	ExcludeBanks []string
 }
 
 type offerRepository struct {
@@ -28,12 +34,71 @@ func (r *offerRepository) Create(ctx context.Context, offer *models.Offer) error
 	return r.db.WithContext(ctx).Create(offer).Error
 }
 
-func (r *offerRepository) List(ctx context.Context, filter OfferListFilter) ([]models.Offer, error) {
+// Claim reserves offers matching the filter that are due for polling for the lease worker,
+// the longest waiting first.
+func (r *offerRepository) Claim(ctx context.Context, filter OfferListFilter, lease Lease) ([]models.Offer, error) {
+	condition := "status = ? AND next_poll_at <= ?"
+	args := []any{filter.Status, time.Now()}
+	if len(filter.ExcludeBanks) > 0 {
+		condition += " AND bank NOT IN ?"
+		args = append(args, filter.ExcludeBanks)
+	}
+
+	var offers []models.Offer
+	err := claim(ctx, r.db, &offers, "offers", condition, "next_poll_at", lease, args...)
+	return offers, err
+}
+
+// Reschedule stores when the offer is polled next and releases it.
+func (r *offerRepository) Reschedule(ctx context.Context, id string, pollAttempts int, nextPollAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Offer{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"poll_attempts": pollAttempts,
+			"next_poll_at":  nextPollAt,
+			"locked_by":     nil,
+			"locked_until":  nil,
+		}).Error
+}
+
+// TimeOut marks DRAFT offers of the bank created before the given time as timed out and
+// returns them. Offers claimed by a worker are skipped until their lease is released.
+func (r *offerRepository) TimeOut(ctx context.Context, bank string, createdBefore time.Time) ([]models.Offer, error) {
+	now := time.Now()
 	var offers []models.Offer
-	err := r.db.WithContext(ctx).Where("status = ?", filter.Status).Find(&offers).Error
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE offers SET status = ?, updated_at = ?
+		WHERE bank = ? AND status = ? AND created_at < ? AND deleted_at IS NULL
+			AND (locked_until IS NULL OR locked_until < ?)
+		RETURNING *`,
+		models.OfferStatusTimedOut, now, bank, models.OfferStatusDraft, createdBefore, now,
+	).Scan(&offers).Error
 	return offers, err
 }
 
-func (r *offerRepository) Update(ctx context.Context, id string, offer models.Offer) error {
-	return r.db.WithContext(ctx).Model(&models.Offer{}).Where("id = ?", id).Updates(offer).Error
+// ListByExternalID returns offers the bank created with the external ID. IDs are unique per
+// account at the bank only, so offers of merchants with their own accounts may share them.
+func (r *offerRepository) ListByExternalID(ctx context.Context, bank, externalID string) ([]models.Offer, error) {
+	var offers []models.Offer
+	err := r.db.WithContext(ctx).Order("created_at").Find(&offers, "bank = ? AND external_id = ?", bank, externalID).Error
+	return offers, err
+}
+
+func (r *offerRepository) GetByApplication(ctx context.Context, applicationID, id string) (models.Offer, error) {
+	var offer models.Offer
+	err := r.db.WithContext(ctx).First(&offer, "id = ? AND application_id = ?", id, applicationID).Error
+	if err != nil {
+		return models.Offer{}, err
+	}
+	return offer, nil
+}
+
+// UpdateDraft stores the offer state reported by the bank and reports whether it was stored,
+// which it is not once the offer has left DRAFT, e.g. timed out or settled by another update.
+func (r *offerRepository) UpdateDraft(ctx context.Context, id string, offer models.Offer) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Offer{}).
+		Where("id = ? AND status = ?", id, models.OfferStatusDraft).
+		Updates(offer)
+	return result.RowsAffected > 0, result.Error
 }
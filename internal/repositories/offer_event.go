@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"financing-aggregator/internal/models"
+	"gorm.io/gorm"
+)
+
+type OfferEventRepository interface {
+	Create(ctx context.Context, events []models.OfferEvent) error
+	ListByApplication(ctx context.Context, applicationID string) ([]models.OfferEvent, error)
+}
+
+type offerEventRepository struct {
+	db *gorm.DB
+}
+
+func NewOfferEventRepository(db *gorm.DB) OfferEventRepository {
+	return &offerEventRepository{db: db}
+}
+
+func (r *offerEventRepository) Create(ctx context.Context, events []models.OfferEvent) error {
+	return r.db.WithContext(ctx).Create(&events).Error
+}
+
+// ListByApplication returns events of the application in chronological order.
+func (r *offerEventRepository) ListByApplication(ctx context.Context, applicationID string) ([]models.OfferEvent, error) {
+	var events []models.OfferEvent
+	err := r.db.WithContext(ctx).
+		Where("application_id = ?", applicationID).
+		Order("created_at, id").
+		Find(&events).Error
+	return events, err
+}
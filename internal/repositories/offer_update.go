@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// OfferUpdateChannel is the Postgres notification channel relaying offer updates to all
+// replicas.
+const OfferUpdateChannel = "offer_updates"
+
+type OfferUpdateRepository interface {
+	Publish(ctx context.Context, payload []byte) error
+	Listen(ctx context.Context, handle func(payload []byte)) error
+}
+
+type offerUpdateRepository struct {
+	db *gorm.DB
+}
+
+func NewOfferUpdateRepository(db *gorm.DB) OfferUpdateRepository {
+	return &offerUpdateRepository{db: db}
+}
+
+// Publish notifies listeners of all replicas, including this one. Payloads must be shorter
+// than 8000 bytes.
+func (r *offerUpdateRepository) Publish(ctx context.Context, payload []byte) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", OfferUpdateChannel, string(payload)).Error
+}
+
+// Listen passes published payloads to handle until ctx is done or the connection fails. It
+// holds a connection of the pool, as notifications are delivered to the listening session.
+func (r *offerUpdateRepository) Listen(ctx context.Context, handle func(payload []byte)) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.Errorf("listening requires a pgx connection, got %T", driverConn)
+		}
+		pgConn := stdlibConn.Conn()
+
+		if _, err := pgConn.Exec(ctx, "LISTEN "+OfferUpdateChannel); err != nil {
+			return err
+		}
+		defer func() {
+			// The connection returns to the pool, unless it was closed by the failure.
+			if !pgConn.IsClosed() {
+				_, _ = pgConn.Exec(context.Background(), "UNLISTEN "+OfferUpdateChannel)
+			}
+		}()
+
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			handle([]byte(notification.Payload))
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Lease identifies the worker claiming rows and how long claimed rows stay reserved for it.
+type Lease struct {
+	Worker   string
+	Duration time.Duration
+	Limit    int
+}
+
+// claim reserves up to lease.Limit rows of the table matching the condition for the
+// worker and scans them into dest. Candidate rows are selected with FOR UPDATE SKIP LOCKED,
+// so concurrent workers never claim the same row, and rows stay reserved until they are
+// released or the lease expires, e.g. because the worker died.
+func claim(ctx context.Context, db *gorm.DB, dest any, table, condition, order string, lease Lease, args ...any) error {
+	now := time.Now()
+	query := fmt.Sprintf(`
+		UPDATE %[1]s SET locked_by = ?, locked_until = ?
+		WHERE id IN (
+			SELECT id FROM %[1]s
+			WHERE %[2]s AND deleted_at IS NULL AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY %[3]s
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, table, condition, order)
+
+	values := append([]any{lease.Worker, now.Add(lease.Duration)}, args...)
+	values = append(values, now, lease.Limit)
+	return db.WithContext(ctx).Raw(query, values...).Scan(dest).Error
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"financing-aggregator/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SubmissionRepository interface {
+	ClaimDue(ctx context.Context, lease Lease) ([]models.Submission, error)
+	Update(ctx context.Context, submission models.Submission) error
+	Complete(ctx context.Context, submission models.Submission, offer *models.Offer) error
+}
+
+type submissionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubmissionRepository(db *gorm.DB) SubmissionRepository {
+	return &submissionRepository{db: db}
+}
+
+// ClaimDue reserves pending submissions whose next attempt is due for the lease worker
+// and returns them with their applications.
+func (r *submissionRepository) ClaimDue(ctx context.Context, lease Lease) ([]models.Submission, error) {
+	var claimed []models.Submission
+	err := claim(ctx, r.db, &claimed, "submissions", "status = ? AND next_attempt_at <= ?", "next_attempt_at", lease,
+		models.SubmissionStatusPending, time.Now())
+	if err != nil || len(claimed) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(claimed))
+	for _, submission := range claimed {
+		ids = append(ids, submission.ID)
+	}
+
+	var submissions []models.Submission
+	err = r.db.WithContext(ctx).
+		Preload("Application").
+		Where("id IN ?", ids).
+		Order("next_attempt_at").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+func (r *submissionRepository) Update(ctx context.Context, submission models.Submission) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Where("id = ?", submission.ID).
+		Select("status", "attempts", "last_error", "next_attempt_at", "locked_by", "locked_until").
+		Updates(release(submission)).Error
+}
+
+// Complete stores the offer received from the bank and marks the submission as submitted
+// in a single transaction.
+func (r *submissionRepository) Complete(ctx context.Context, submission models.Submission, offer *models.Offer) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(offer).Error; err != nil {
+			return err
+		}
+
+		submission.Status = models.SubmissionStatusSubmitted
+		return tx.Model(&models.Submission{}).
+			Where("id = ?", submission.ID).
+			Select("status", "attempts", "last_error", "locked_by", "locked_until").
+			Updates(release(submission)).Error
+	})
+}
+
+func release(submission models.Submission) models.Submission {
+	submission.LockedBy = nil
+	submission.LockedUntil = nil
+	return submission
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"financing-aggregator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) (bool, error)
+	Get(ctx context.Context, id string) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	UpdateProfile(ctx context.Context, user *models.User) error
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create stores the user and reports whether it was stored, which it is not if the email
+// is already registered.
+func (r *userRepository) Create(ctx context.Context, user *models.User) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(user)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *userRepository) Get(ctx context.Context, id string) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// UpdateProfile stores profile fields of the user, including zero values.
+func (r *userRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Model(user).
+		Select("phone", "monthly_income", "monthly_expenses", "monthly_credit_liabilities", "marital_status", "dependents").
+		Updates(user).Error
+}
@@ -2,137 +2,479 @@ package services
 
 import (
 	"context"
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/config"
 	"financing-aggregator/internal/controllers/ws"
 	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/exchange"
 	"financing-aggregator/internal/mapper"
 	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/ranking"
 	"financing-aggregator/internal/repositories"
+	"financing-aggregator/internal/verification"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownBank            = errors.New("unknown bank")
+	ErrUnsupportedCurrency    = errors.New("no bank supports the currency")
+	ErrDuplicateApplication   = errors.New("application duplicates a recently submitted one")
+	ErrUnknownDuplicatePolicy = errors.New("unknown duplicate policy")
+)
+
+const (
+	DuplicatePolicyReturn = "return"
+	DuplicatePolicyReject = "reject"
 )
 
 type ApplicationService interface {
 	SubmitApplication(ctx context.Context, app dto.ApplicationDTO) (dto.ApplicationDTO, error)
-	GetApplication(ctx context.Context, id string) (dto.ApplicationDTO, error)
+	GetApplication(ctx context.Context, principal auth.Principal, id, sortBy string) (dto.ApplicationDTO, error)
+	GetApplicationTimeline(ctx context.Context, principal auth.Principal, id string) ([]dto.OfferEventDTO, error)
+	GetOfferSchedule(ctx context.Context, principal auth.Principal, applicationID, offerID string) ([]dto.InstallmentDTO, error)
 	UpdateApplicationStatuses(ctx context.Context)
+	ProcessSubmissions(ctx context.Context)
+	ExpireOffers(ctx context.Context)
+	HandleBankWebhook(ctx context.Context, merchantID, bankName, signature string, body []byte) error
 }
 
 type applicationService struct {
 	logger          *zap.Logger
+	cfg             *config.Config
 	banks           map[string]banks.Bank
+	newBank         banks.Factory
+	tenantBanks     tenantBanks
+	webhookOnly     []string
+	pollSlots       map[string]chan struct{}
+	ranker          *ranking.Ranker
+	verifier        *verification.Verifier
 	applicationRepo repositories.ApplicationRepository
 	offerRepo       repositories.OfferRepository
+	submissionRepo  repositories.SubmissionRepository
+	offerEventRepo  repositories.OfferEventRepository
+	merchantRepo    repositories.MerchantRepository
 	wsHandler       ws.WebSocketHandler
 }
 
 func NewApplicationService(
 	logger *zap.Logger,
+	cfg *config.Config,
 	allBanks []banks.Bank,
+	newBank banks.Factory,
+	ranker *ranking.Ranker,
 	applicationRepo repositories.ApplicationRepository,
 	offerRepo repositories.OfferRepository,
+	submissionRepo repositories.SubmissionRepository,
+	offerEventRepo repositories.OfferEventRepository,
+	merchantRepo repositories.MerchantRepository,
 	wsHandler ws.WebSocketHandler,
-) ApplicationService {
+) (ApplicationService, error) {
+	if cfg.Queue.Lease > 0 && (cfg.Queue.SubmitTimeout <= 0 || cfg.Queue.SubmitTimeout >= cfg.Queue.Lease) {
+		return nil, errors.Errorf("queue submit timeout %s must be positive and shorter than the lease %s",
+			cfg.Queue.SubmitTimeout, cfg.Queue.Lease)
+	}
+
+	switch cfg.Duplicates.Policy {
+	case "", DuplicatePolicyReturn, DuplicatePolicyReject:
+	default:
+		return nil, errors.Wrap(ErrUnknownDuplicatePolicy, cfg.Duplicates.Policy)
+	}
+
 	bankMap := lo.SliceToMap(allBanks, func(b banks.Bank) (string, banks.Bank) {
 		return b.Name(), b
 	})
 
+	var webhookOnly []string
+	pollSlots := make(map[string]chan struct{})
+	for _, b := range allBanks {
+		if receiver, ok := b.(banks.WebhookReceiver); ok && receiver.PollingDisabled() {
+			webhookOnly = append(webhookOnly, b.Name())
+		}
+		if limit := cfg.Banks[b.Name()].Polling.MaxConcurrency; limit > 0 {
+			pollSlots[b.Name()] = make(chan struct{}, limit)
+		}
+	}
+
 	return &applicationService{
 		logger:          logger,
+		cfg:             cfg,
 		banks:           bankMap,
+		newBank:         newBank,
+		tenantBanks:     tenantBanks{entries: make(map[uuid.UUID]tenantBanksEntry)},
+		webhookOnly:     webhookOnly,
+		pollSlots:       pollSlots,
+		ranker:          ranker,
+		verifier:        verification.New(cfg.OfferVerification),
 		applicationRepo: applicationRepo,
 		offerRepo:       offerRepo,
+		submissionRepo:  submissionRepo,
+		offerEventRepo:  offerEventRepo,
+		merchantRepo:    merchantRepo,
 		wsHandler:       wsHandler,
-	}
+	}, nil
 }
 
+// SubmitApplication stores the application together with a pending submission for every
+// bank of the merchant, if any, supporting its currency, so that delivery to banks survives
+// restarts and is retried by ProcessSubmissions.
 func (s *applicationService) SubmitApplication(ctx context.Context, app dto.ApplicationDTO) (dto.ApplicationDTO, error) {
+	if app.Currency == "" {
+		app.Currency = models.DefaultCurrency
+	}
+
 	appModel := mapper.MapApplicationDTOToModel(app)
-	if err := s.applicationRepo.Create(ctx, &appModel); err != nil {
+	available, err := s.banksFor(ctx, appModel.MerchantID)
+	if err != nil {
+		return dto.ApplicationDTO{}, err
+	}
+	bankNames := lo.Filter(lo.Keys(available), func(name string, _ int) bool {
+		return s.supportsCurrency(name, app.Currency)
+	})
+	if len(bankNames) == 0 {
+		return dto.ApplicationDTO{}, errors.Wrap(ErrUnsupportedCurrency, app.Currency)
+	}
+
+	slices.Sort(bankNames)
+	for _, name := range bankNames {
+		appModel.Submissions = append(appModel.Submissions, models.Submission{
+			Bank:          name,
+			Status:        models.SubmissionStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+	appModel.Events = []models.OfferEvent{{
+		Type:    models.OfferEventSubmitted,
+		Details: fmt.Sprintf("submitted to %d banks", len(bankNames)),
+	}}
+
+	duplicate, err := s.createApplication(ctx, &appModel)
+	if err != nil {
 		s.logger.Error("failed to create application", zap.Error(err))
 		return dto.ApplicationDTO{}, fmt.Errorf("failed to create application: %v", err)
 	}
+	if duplicate != nil {
+		return s.duplicateApplication(*duplicate)
+	}
 
-	for _, bank := range s.banks {
-		go func(b banks.Bank, a dto.ApplicationDTO) {
-			ctx := context.Background()
+	app.ID = appModel.ID.String()
+	return app, nil
+}
 
-			offer, err := b.SubmitApplication(ctx, a)
-			if err != nil {
-				s.logger.Error("failed to submit application", zap.Error(err), zap.String("bank", b.Name()), zap.String("id", a.ID))
-				return
-			}
+// createApplication stores the application, unless duplicate detection is enabled and it
+// duplicates a recent one, which is returned instead.
+func (s *applicationService) createApplication(ctx context.Context, app *models.Application) (*models.Application, error) {
+	if s.cfg.Duplicates.Window <= 0 {
+		return nil, s.applicationRepo.Create(ctx, app)
+	}
+	return s.applicationRepo.CreateUnlessDuplicate(ctx, app, time.Now().Add(-s.cfg.Duplicates.Window))
+}
 
-			offerModel := mapper.MapOfferDTOToModel(offer, appModel.ID)
-			if err := s.offerRepo.Create(ctx, &offerModel); err != nil {
-				s.logger.Error("failed to create offer", zap.Error(err), zap.String("bank", b.Name()), zap.String("id", a.ID))
-				return
-			}
-		}(bank, app)
+// duplicateApplication returns the existing application with its ranked offers, or fails
+// with ErrDuplicateApplication if duplicates are rejected.
+func (s *applicationService) duplicateApplication(existing models.Application) (dto.ApplicationDTO, error) {
+	s.logger.Info("duplicate application submitted", zap.String("id", existing.ID.String()))
+	if s.cfg.Duplicates.Policy == DuplicatePolicyReject {
+		return dto.ApplicationDTO{}, errors.Wrapf(ErrDuplicateApplication, "application %s submitted at %s",
+			existing.ID, existing.CreatedAt.Format(time.RFC3339))
 	}
 
-	app.ID = appModel.ID.String()
+	app := mapper.MapApplicationModelToDTO(existing)
+	app.Duplicate = true
+	var err error
+	if app.Offers, err = s.ranker.Rank(app.Offers, ""); err != nil {
+		return dto.ApplicationDTO{}, err
+	}
 	return app, nil
 }
 
-func (s *applicationService) GetApplication(ctx context.Context, id string) (dto.ApplicationDTO, error) {
+// supportsCurrency reports whether the bank accepts applications in the currency.
+func (s *applicationService) supportsCurrency(bank, currency string) bool {
+	currencies := s.cfg.Banks[bank].Currencies
+	if len(currencies) == 0 {
+		currencies = []string{models.DefaultCurrency}
+	}
+	return slices.ContainsFunc(currencies, func(c string) bool {
+		return strings.EqualFold(c, currency)
+	})
+}
+
+// GetApplication returns the application with its processed offers ranked from best to
+// worst, sorted by sortBy if it is not empty.
+func (s *applicationService) GetApplication(ctx context.Context, principal auth.Principal, id, sortBy string) (dto.ApplicationDTO, error) {
+	if uuid.Validate(id) != nil {
+		return dto.ApplicationDTO{}, errors.Wrap(gorm.ErrRecordNotFound, "invalid application ID")
+	}
+
 	application, err := s.applicationRepo.GetWithProcessedOffers(ctx, id)
 	if err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.ApplicationDTO{}, err
-		}
-		return dto.ApplicationDTO{}, fmt.Errorf("failed to get application: %v", err)
+		return dto.ApplicationDTO{}, errors.Wrap(err, "failed to get application")
+	}
+	if err := authorizeApplication(principal, application); err != nil {
+		return dto.ApplicationDTO{}, err
 	}
 
-	return mapper.MapApplicationModelToDTO(application), nil
+	app := mapper.MapApplicationModelToDTO(application)
+	if app.Offers, err = s.ranker.Rank(app.Offers, sortBy); err != nil {
+		return dto.ApplicationDTO{}, err
+	}
+	return app, nil
 }
 
+// UpdateApplicationStatuses polls banks for due draft offers claimed by this worker. Offers
+// are polled concurrently by a bounded number of workers within the configured run timeout.
 func (s *applicationService) UpdateApplicationStatuses(ctx context.Context) {
-	offers, err := s.offerRepo.List(ctx, repositories.OfferListFilter{
-		Status: models.OfferStatusDraft,
-	})
+	offers, err := s.offerRepo.Claim(ctx, repositories.OfferListFilter{
+		Status:       models.OfferStatusDraft,
+		ExcludeBanks: s.webhookOnly,
+	}, s.lease(s.cfg.Queue.PollBatchSize))
 	if err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			s.logger.Error("failed to list draft offers", zap.Error(err))
-		}
+		s.logger.Error("failed to claim draft offers", zap.Error(err))
 		return
 	}
 
+	pollCtx := ctx
+	if s.cfg.Queue.PollTimeout > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, s.cfg.Queue.PollTimeout)
+		defer cancel()
+	}
+
+	jobs := make(chan models.Offer)
+	var wg sync.WaitGroup
+	for range max(s.cfg.Queue.PollWorkers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offer := range jobs {
+				s.pollOffer(ctx, pollCtx, offer)
+			}
+		}()
+	}
+
 	for _, offer := range offers {
-		bank, ok := s.banks[offer.Bank]
-		if !ok {
-			s.logger.Error("offer belongs to unknown bank", zap.String("bank", offer.Bank))
-			continue
+		jobs <- offer
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// pollOffer polls the offer status if the run has not timed out and a bank slot is free
+// in time, and schedules the next poll. Offers that were not polled are due again at once.
+func (s *applicationService) pollOffer(ctx, pollCtx context.Context, offer models.Offer) {
+	attempts, nextPollAt := offer.PollAttempts, time.Now()
+	if s.acquirePollSlot(pollCtx, offer.Bank) {
+		s.refreshOffer(ctx, pollCtx, offer)
+		s.releasePollSlot(offer.Bank)
+
+		attempts++
+		nextPollAt = time.Now().Add(s.pollInterval(offer.Bank, attempts))
+	}
+
+	if err := s.offerRepo.Reschedule(ctx, offer.ID.String(), attempts, nextPollAt); err != nil {
+		s.logger.Error("failed to reschedule offer", zap.Error(err), zap.String("id", offer.ID.String()))
+	}
+}
+
+func (s *applicationService) acquirePollSlot(ctx context.Context, bank string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	slots, ok := s.pollSlots[bank]
+	if !ok {
+		return true
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *applicationService) releasePollSlot(bank string) {
+	if slots, ok := s.pollSlots[bank]; ok {
+		<-slots
+	}
+}
+
+// refreshOffer asks the bank for the offer within the run deadline of pollCtx. The answer is
+// stored with ctx, so that it is not lost once the bank has answered.
+func (s *applicationService) refreshOffer(ctx, pollCtx context.Context, offer models.Offer) {
+	available, err := s.banksFor(ctx, offer.MerchantID)
+	if err != nil {
+		s.logger.Error("failed to get banks of offer", zap.Error(err), zap.String("id", offer.ID.String()))
+		return
+	}
+
+	bank, ok := available[offer.Bank]
+	if !ok {
+		s.logger.Error("offer belongs to unknown bank", zap.String("bank", offer.Bank))
+		return
+	}
+
+	bankOffer, err := bank.GetApplication(pollCtx, offer.ExternalID)
+	if err != nil {
+		s.logger.Error("failed to get application from bank", zap.Error(err), zap.String("bank", offer.Bank), zap.String("id", offer.ExternalID))
+		return
+	}
+
+	if err := s.applyBankOffer(ctx, offer, bankOffer); err != nil {
+		s.logger.Error("failed to get update offer", zap.Error(err), zap.String("bank", offer.Bank), zap.String("id", offer.ID.String()))
+	}
+}
+
+// pollInterval returns the delay before the next poll of an offer of the bank that has
+// been polled the given number of times. The interval grows without limit if the policy
+// has no maximum interval.
+func (s *applicationService) pollInterval(bank string, attempts int) time.Duration {
+	policy := s.cfg.Banks[bank].Polling
+	interval := policy.InitialInterval
+	for i := 0; i < attempts && policy.Multiplier > 1; i++ {
+		if policy.MaxInterval > 0 && interval >= policy.MaxInterval {
+			break
+		}
+		next := float64(interval) * policy.Multiplier
+		if next >= math.MaxInt64 {
+			break
 		}
+		interval = time.Duration(next)
+	}
+	if policy.MaxInterval > 0 {
+		return min(interval, policy.MaxInterval)
+	}
+	return interval
+}
+
+func (s *applicationService) lease(limit int) repositories.Lease {
+	return repositories.Lease{
+		Worker:   s.cfg.Queue.WorkerID,
+		Duration: s.cfg.Queue.Lease,
+		Limit:    limit,
+	}
+}
 
-		bankOffer, err := bank.GetApplication(ctx, offer.ExternalID)
+// HandleBankWebhook applies the offer state pushed by the bank account of the merchant, or by
+// the shared account if merchantID is empty.
+func (s *applicationService) HandleBankWebhook(ctx context.Context, merchantID, bankName, signature string, body []byte) error {
+	var tenant *uuid.UUID
+	if merchantID != "" {
+		id, err := uuid.Parse(merchantID)
 		if err != nil {
-			s.logger.Error("failed to get application from bank", zap.Error(err), zap.String("bank", offer.Bank), zap.String("id", offer.ExternalID))
-			continue
+			return errors.Wrap(gorm.ErrRecordNotFound, "merchant not found")
 		}
+		tenant = &id
+	}
 
-		if bankOffer.Status == offer.Status {
-			continue
-		}
+	available, err := s.banksFor(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	bank, ok := available[bankName]
+	if !ok {
+		return ErrUnknownBank
+	}
+	receiver, ok := bank.(banks.WebhookReceiver)
+	if !ok {
+		return banks.ErrWebhooksNotSupported
+	}
 
-		model := mapper.MapOfferDTOToModel(bankOffer, offer.ApplicationID)
-		if bankOffer.NumberOfPayments == 0 {
-			model.Status = models.OfferStatusDeclined
-		}
+	bankOffer, err := receiver.ParseWebhook(signature, body)
+	if err != nil {
+		return err
+	}
+
+	offer, err := s.webhookOffer(ctx, bank, bankName, bankOffer.ExternalID)
+	if err != nil {
+		return err
+	}
+
+	return s.applyBankOffer(ctx, offer, bankOffer)
+}
 
-		err = s.offerRepo.Update(ctx, offer.ID.String(), model)
+// webhookOffer returns the offer with the external ID that was created through the bank, so
+// that a webhook of one account never updates offers of another account at the same bank.
+func (s *applicationService) webhookOffer(ctx context.Context, bank banks.Bank, bankName, externalID string) (models.Offer, error) {
+	offers, err := s.offerRepo.ListByExternalID(ctx, bankName, externalID)
+	if err != nil {
+		return models.Offer{}, errors.Wrap(err, "failed to get offers")
+	}
+
+	for _, offer := range offers {
+		offerBanks, err := s.banksFor(ctx, offer.MerchantID)
 		if err != nil {
-			s.logger.Error("failed to get update offer", zap.Error(err), zap.String("bank", offer.Bank), zap.String("id", offer.ID.String()))
-			continue
+			return models.Offer{}, err
 		}
+		if offerBanks[bankName] == bank {
+			return offer, nil
+		}
+	}
+	return models.Offer{}, errors.Wrap(gorm.ErrRecordNotFound, "offer not found")
+}
 
-		if bankOffer.Status == models.OfferStatusDeclined {
-			continue
+// applyBankOffer verifies and stores the offer state reported by the bank, if its status or
+// terms have changed, records the changes and notifies subscribers unless the offer is
+// quarantined. Offers that have left DRAFT are final, so late or replayed bank updates
+// of them are ignored.
+func (s *applicationService) applyBankOffer(ctx context.Context, offer models.Offer, bankOffer dto.OfferDTO) error {
+	if offer.Status != models.OfferStatusDraft {
+		s.logger.Info("bank update of settled offer ignored", zap.String("id", offer.ID.String()), zap.String("status", offer.Status))
+		return nil
+	}
+
+	model := mapper.MapOfferDTOToModel(bankOffer, offer.ApplicationID)
+	if bankOffer.Status != offer.Status && bankOffer.NumberOfPayments == 0 {
+		model.Status = models.OfferStatusDeclined
+	}
+
+	model.ID = offer.ID
+	model.Currency = offer.Currency
+
+	changeEvents := offerChangeEvents(offer, model)
+	if len(changeEvents) == 0 {
+		return nil
+	}
+
+	var verificationEvents []models.OfferEvent
+	if model.NumberOfPayments > 0 {
+		application, err := s.applicationRepo.Get(ctx, offer.ApplicationID.String())
+		if err != nil {
+			return errors.Wrap(err, "failed to get application")
 		}
+		verificationEvents = s.verifyOffer(&model, application.Amount)
+	}
 
-		s.wsHandler.BroadcastNewOffer(offer.ApplicationID.String(), mapper.MapOfferDTOToResponse(bankOffer))
+	updated, err := s.offerRepo.UpdateDraft(ctx, offer.ID.String(), model)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		s.logger.Info("bank update of settled offer ignored", zap.String("id", offer.ID.String()))
+		return nil
+	}
+	s.recordEvents(ctx, append(changeEvents, verificationEvents...)...)
+
+	if bankOffer.Status == models.OfferStatusDeclined || model.Quarantined {
+		return nil
 	}
+
+	s.broadcastOffer(ctx, offer, mapper.MapOfferDTOToResponse(mapper.MapOfferModelToDTO(model)))
+	return nil
+}
+
+func (s *applicationService) broadcastOffer(ctx context.Context, offer models.Offer, response exchange.OfferResponse) {
+	s.wsHandler.BroadcastNewOffer(ctx, offer.ApplicationID.String(), response)
+	s.recordEvents(ctx, newOfferEvent(offer, models.OfferEventBroadcast, response.Status, ""))
 }
@@ -2,20 +2,26 @@ package services
 
 import (
 	"context"
+	"financing-aggregator/internal/auth"
 	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/config"
 	"financing-aggregator/internal/dto"
 	"financing-aggregator/internal/exchange"
 	mock_banks "financing-aggregator/internal/mocks/banks"
 	mock_ws "financing-aggregator/internal/mocks/controllers/ws"
 	mock_repositories "financing-aggregator/internal/mocks/repositories"
 	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/money"
+	"financing-aggregator/internal/ranking"
 	"financing-aggregator/internal/repositories"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -27,11 +33,20 @@ type applicationServiceTestSuite struct {
 
 	applicationRepository *mock_repositories.MockApplicationRepository
 	offerRepository       *mock_repositories.MockOfferRepository
+	submissionRepository  *mock_repositories.MockSubmissionRepository
+	offerEventRepository  *mock_repositories.MockOfferEventRepository
+	merchantRepository    *mock_repositories.MockMerchantRepository
 	banks                 []banks.Bank
 	bank1                 *mock_banks.MockBank
 	bank2                 *mock_banks.MockBank
 	wsHandler             *mock_ws.MockWebSocketHandler
 
+	// tenantBank is created for banks with settings of a merchant, with tenantBankConfigs,
+	// or tenantWebhookBank if set.
+	tenantBank        *mock_banks.MockBank
+	tenantWebhookBank *webhookBank
+	tenantBankConfigs []config.BankConfig
+
 	service *applicationService
 }
 
@@ -45,66 +60,321 @@ func (s *applicationServiceTestSuite) SetupTest() {
 
 	s.applicationRepository = mock_repositories.NewMockApplicationRepository(s.ctrl)
 	s.offerRepository = mock_repositories.NewMockOfferRepository(s.ctrl)
+	s.submissionRepository = mock_repositories.NewMockSubmissionRepository(s.ctrl)
+	s.offerEventRepository = mock_repositories.NewMockOfferEventRepository(s.ctrl)
+	s.merchantRepository = mock_repositories.NewMockMerchantRepository(s.ctrl)
 	s.bank1 = mock_banks.NewMockBank(s.ctrl)
 	s.bank2 = mock_banks.NewMockBank(s.ctrl)
 	s.banks = []banks.Bank{s.bank1, s.bank2}
 	s.wsHandler = mock_ws.NewMockWebSocketHandler(s.ctrl)
+	s.tenantBank = mock_banks.NewMockBank(s.ctrl)
+	s.tenantWebhookBank = nil
+	s.tenantBankConfigs = nil
 
 	s.bank1.EXPECT().Name().Return("bank1").AnyTimes()
 	s.bank2.EXPECT().Name().Return("bank2").AnyTimes()
 
-	s.service = NewApplicationService(s.logger, s.banks, s.applicationRepository, s.offerRepository, s.wsHandler).(*applicationService)
+	s.service = s.newService(s.banks)
 }
 
 func (s *applicationServiceTestSuite) TearDownTest() {
 	s.ctrl.Finish()
 }
 
+func (s *applicationServiceTestSuite) newService(allBanks []banks.Bank) *applicationService {
+	return lo.Must(NewApplicationService(
+		s.logger,
+		getTestConfig(),
+		allBanks,
+		s.newBank,
+		lo.Must(ranking.New(nil)),
+		s.applicationRepository,
+		s.offerRepository,
+		s.submissionRepository,
+		s.offerEventRepository,
+		s.merchantRepository,
+		s.wsHandler,
+	)).(*applicationService)
+}
+
+func (s *applicationServiceTestSuite) newBank(_ string, cfg config.BankConfig) (banks.Bank, error) {
+	s.tenantBankConfigs = append(s.tenantBankConfigs, cfg)
+	if s.tenantWebhookBank != nil {
+		return *s.tenantWebhookBank, nil
+	}
+	return s.tenantBank, nil
+}
+
 func (s *applicationServiceTestSuite) Test_SubmitApplication() {
 	applicationDTO := getTestApplicationDTO()
-	offerDTO1 := getTestOfferDTO("bank1")
-	offerDTO2 := getTestOfferDTO("bank2")
 
 	s.Run("application submitted", func() {
-		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
-		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO1, nil)
-		s.bank2.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO2, nil)
-		s.offerRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, app *models.Application) error {
+			s.Len(app.Submissions, 2)
+			for i, bank := range []string{"bank1", "bank2"} {
+				s.Equal(bank, app.Submissions[i].Bank)
+				s.Equal(models.SubmissionStatusPending, app.Submissions[i].Status)
+				s.WithinDuration(time.Now(), app.Submissions[i].NextAttemptAt, time.Second)
+			}
+			s.Len(app.Events, 1)
+			s.Equal(models.OfferEventSubmitted, app.Events[0].Type)
+			return nil
+		})
 
 		_, err := s.service.SubmitApplication(context.Background(), applicationDTO)
-		time.Sleep(1 * time.Second)
 		s.NoError(err)
 	})
 
+	s.Run("application submitted to banks supporting its currency", func() {
+		application := getTestApplicationDTO()
+		application.Currency = "SEK"
+
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, app *models.Application) error {
+			s.Equal("SEK", app.Currency)
+			s.Len(app.Submissions, 1)
+			s.Equal("bank2", app.Submissions[0].Bank)
+			return nil
+		})
+
+		actual, err := s.service.SubmitApplication(context.Background(), application)
+		s.NoError(err)
+		s.Equal("SEK", actual.Currency)
+	})
+
+	s.Run("application linked to user and merchant", func() {
+		application := getTestApplicationDTO()
+		application.UserID = uuid.NewString()
+		application.MerchantID = uuid.NewString()
+
+		s.merchantRepository.EXPECT().Get(gomock.Any(), application.MerchantID).Return(models.Merchant{}, nil)
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, app *models.Application) error {
+			s.Require().NotNil(app.UserID)
+			s.Equal(application.UserID, app.UserID.String())
+			s.Require().NotNil(app.MerchantID)
+			s.Equal(application.MerchantID, app.MerchantID.String())
+			return nil
+		})
+
+		actual, err := s.service.SubmitApplication(context.Background(), application)
+		s.NoError(err)
+		s.Equal(application.UserID, actual.UserID)
+		s.Equal(application.MerchantID, actual.MerchantID)
+	})
+
+	s.Run("default currency used", func() {
+		application := getTestApplicationDTO()
+		application.Currency = ""
+
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, app *models.Application) error {
+			s.Equal(models.DefaultCurrency, app.Currency)
+			s.Len(app.Submissions, 2)
+			return nil
+		})
+
+		_, err := s.service.SubmitApplication(context.Background(), application)
+		s.NoError(err)
+	})
+
+	s.Run("error occurs because no bank supports currency", func() {
+		application := getTestApplicationDTO()
+		application.Currency = "USD"
+
+		_, err := s.service.SubmitApplication(context.Background(), application)
+		s.ErrorIs(err, ErrUnsupportedCurrency)
+	})
+
 	s.Run("error occurs while saving application", func() {
 		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 
 		_, err := s.service.SubmitApplication(context.Background(), applicationDTO)
-		time.Sleep(1 * time.Second)
 		s.Error(err)
 		s.Contains(err.Error(), "db error")
 	})
+}
 
-	s.Run("error occurs while submitting application to bank", func() {
-		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
-		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO1, errors.New("bank error"))
-		s.bank2.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO2, nil)
-		s.offerRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+func (s *applicationServiceTestSuite) Test_SubmitApplication_Duplicates() {
+	s.service.cfg.Duplicates.Window = 10 * time.Minute
+	existing := getTestApplicationModel()
+	existing.ID = uuid.New()
+	existing.Offers = []models.Offer{getTestOfferModel("bank1")}
 
-		_, err := s.service.SubmitApplication(context.Background(), applicationDTO)
-		time.Sleep(1 * time.Second)
+	s.Run("application created if it is not a duplicate", func() {
+		s.applicationRepository.EXPECT().CreateUnlessDuplicate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, app *models.Application, since time.Time) (*models.Application, error) {
+				s.WithinDuration(time.Now().Add(-10*time.Minute), since, time.Second)
+				s.Len(app.Submissions, 2)
+				return nil, nil
+			})
+
+		actual, err := s.service.SubmitApplication(context.Background(), getTestApplicationDTO())
 		s.NoError(err)
+		s.False(actual.Duplicate)
 	})
 
-	s.Run("error occurs while saving offer", func() {
-		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
-		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO1, nil)
-		s.bank2.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO2, nil)
-		s.offerRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("offer save error")).AnyTimes()
+	s.Run("existing application returned", func() {
+		s.service.cfg.Duplicates.Policy = DuplicatePolicyReturn
+		s.applicationRepository.EXPECT().CreateUnlessDuplicate(gomock.Any(), gomock.Any(), gomock.Any()).Return(&existing, nil)
 
-		_, err := s.service.SubmitApplication(context.Background(), applicationDTO)
-		time.Sleep(1 * time.Second)
+		actual, err := s.service.SubmitApplication(context.Background(), getTestApplicationDTO())
 		s.NoError(err)
+		s.True(actual.Duplicate)
+		s.Equal(existing.ID.String(), actual.ID)
+		s.Len(actual.Offers, 1)
+		s.True(actual.Offers[0].Recommended)
+	})
+
+	s.Run("error occurs because duplicates are rejected", func() {
+		s.service.cfg.Duplicates.Policy = DuplicatePolicyReject
+		s.applicationRepository.EXPECT().CreateUnlessDuplicate(gomock.Any(), gomock.Any(), gomock.Any()).Return(&existing, nil)
+
+		_, err := s.service.SubmitApplication(context.Background(), getTestApplicationDTO())
+		s.ErrorIs(err, ErrDuplicateApplication)
+		s.ErrorContains(err, existing.ID.String())
+	})
+
+	s.Run("error occurs while saving application", func() {
+		s.applicationRepository.EXPECT().CreateUnlessDuplicate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, err := s.service.SubmitApplication(context.Background(), getTestApplicationDTO())
+		s.ErrorContains(err, "db error")
+	})
+
+	s.Run("error occurs because duplicate policy is unknown", func() {
+		cfg := getTestConfig()
+		cfg.Duplicates.Policy = "ignore"
+
+		_, err := NewApplicationService(s.logger, cfg, s.banks, s.newBank, lo.Must(ranking.New(nil)), s.applicationRepository,
+			s.offerRepository, s.submissionRepository, s.offerEventRepository, s.merchantRepository, s.wsHandler)
+		s.ErrorIs(err, ErrUnknownDuplicatePolicy)
+		s.ErrorContains(err, "ignore")
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_ProcessSubmissions() {
+	applicationDTO := getTestApplicationDTO()
+	offerDTO := getTestOfferDTO("bank1")
+
+	s.Run("application submitted to bank", func() {
+		submission := getTestSubmissionModel("bank1", 0)
+		completed := getTestSubmissionModel("bank1", 1)
+
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO, nil)
+		s.submissionRepository.EXPECT().Complete(gomock.Any(), completed, gomock.Any()).DoAndReturn(func(_ context.Context, _ models.Submission, offer *models.Offer) error {
+			s.Equal(offerDTO.ExternalID, offer.ExternalID)
+			s.Equal(submission.ApplicationID, offer.ApplicationID)
+			s.Equal("EUR", offer.Currency)
+			s.WithinDuration(time.Now().Add(time.Second), offer.NextPollAt, 100*time.Millisecond)
+			s.Equal(models.OfferVerificationMismatch, offer.Verification)
+			return nil
+		})
+		s.expectEvents(models.OfferEventBankResponse, models.OfferEventVerificationFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("error occurs while listing submissions", func() {
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return(nil, errors.New("db error"))
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("error occurs while submitting application to bank", func() {
+		submission := getTestSubmissionModel("bank1", 1)
+
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(dto.OfferDTO{}, errors.New("bank error"))
+		s.submissionRepository.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, actual models.Submission) error {
+			s.Equal(models.SubmissionStatusPending, actual.Status)
+			s.Equal(2, actual.Attempts)
+			s.Equal("bank error", actual.LastError)
+			s.WithinDuration(time.Now().Add(2*time.Second), actual.NextAttemptAt, 100*time.Millisecond)
+			return nil
+		})
+		s.expectEvents(models.OfferEventSubmissionFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("submission failed after last attempt", func() {
+		submission := getTestSubmissionModel("bank1", 2)
+
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(dto.OfferDTO{}, errors.New("bank error"))
+		s.submissionRepository.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, actual models.Submission) error {
+			s.Equal(models.SubmissionStatusFailed, actual.Status)
+			s.Equal(3, actual.Attempts)
+			return nil
+		})
+		s.expectEvents(models.OfferEventSubmissionFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("error occurs while saving offer", func() {
+		submission := getTestSubmissionModel("bank1", 0)
+
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).Return(offerDTO, nil)
+		s.submissionRepository.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("offer save error"))
+		s.submissionRepository.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, actual models.Submission) error {
+			s.Equal(models.SubmissionStatusPending, actual.Status)
+			s.Equal("offer save error", actual.LastError)
+			return nil
+		})
+		s.expectEvents(models.OfferEventSubmissionFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("submission belongs to unknown bank", func() {
+		submission := getTestSubmissionModel("bank3", 0)
+
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.submissionRepository.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, actual models.Submission) error {
+			s.Equal(models.SubmissionStatusFailed, actual.Status)
+			return nil
+		})
+		s.expectEvents(models.OfferEventSubmissionFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("batch ended before lease expires", func() {
+		service := s.newService(s.banks)
+		service.cfg.Queue.Lease = 200 * time.Millisecond
+		service.cfg.Queue.SubmitTimeout = 50 * time.Millisecond
+		slow := getTestSubmissionModel("bank1", 0)
+		unsent := getTestSubmissionModel("bank2", 0)
+		unsent.ID = uuid.New()
+
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), repositories.Lease{Worker: "worker-1", Duration: 200 * time.Millisecond, Limit: 10}).
+			Return([]models.Submission{slow, unsent}, nil)
+		s.bank1.EXPECT().SubmitApplication(gomock.Any(), applicationDTO).DoAndReturn(func(ctx context.Context, _ dto.ApplicationDTO) (dto.OfferDTO, error) {
+			<-ctx.Done()
+			return dto.OfferDTO{}, ctx.Err()
+		})
+		s.submissionRepository.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, actual models.Submission) error {
+			s.NoError(ctx.Err())
+			s.Equal(slow.ID, actual.ID)
+			s.Equal(1, actual.Attempts)
+			return nil
+		})
+		s.expectEvents(models.OfferEventSubmissionFailed)
+		s.submissionRepository.EXPECT().Update(gomock.Any(), unsent).Return(nil)
+
+		start := time.Now()
+		service.ProcessSubmissions(context.Background())
+		s.Less(time.Since(start), service.cfg.Queue.Lease)
+	})
+
+	s.Run("error occurs because submit timeout is not shorter than lease", func() {
+		cfg := getTestConfig()
+		cfg.Queue.SubmitTimeout = cfg.Queue.Lease
+
+		_, err := NewApplicationService(s.logger, cfg, s.banks, s.newBank, lo.Must(ranking.New(nil)), s.applicationRepository,
+			s.offerRepository, s.submissionRepository, s.offerEventRepository, s.merchantRepository, s.wsHandler)
+		s.ErrorContains(err, "shorter than the lease")
 	})
 }
 
@@ -114,23 +384,95 @@ func (s *applicationServiceTestSuite) Test_GetApplication() {
 
 	s.Run("application found", func() {
 		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(applicationModel, nil)
-		actual, err := s.service.GetApplication(context.Background(), applicationDTO.ID)
+		actual, err := s.service.GetApplication(context.Background(), getTestAdminPrincipal(), applicationDTO.ID, "")
 		s.NoError(err)
 		s.NotNil(actual)
 		s.Equal(applicationDTO, actual)
 	})
 
+	s.Run("offers ranked", func() {
+		cheap, expensive := getTestOfferModel("bank1"), getTestOfferModel("bank2")
+		cheap.AnnualPercentageRate, expensive.AnnualPercentageRate = 5, 15
+		withOffers := getTestApplicationModel()
+		withOffers.Offers = []models.Offer{expensive, cheap}
+
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(withOffers, nil)
+		actual, err := s.service.GetApplication(context.Background(), getTestAdminPrincipal(), applicationDTO.ID, ranking.CriterionAPR)
+		s.NoError(err)
+		s.Len(actual.Offers, 2)
+		s.Equal("bank1", actual.Offers[0].Bank)
+		s.True(actual.Offers[0].Recommended)
+		s.False(actual.Offers[1].Recommended)
+	})
+
+	s.Run("error occurs because sort criterion is unknown", func() {
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(applicationModel, nil)
+		_, err := s.service.GetApplication(context.Background(), getTestAdminPrincipal(), applicationDTO.ID, "color")
+		s.ErrorIs(err, ranking.ErrUnknownCriterion)
+	})
+
+	s.Run("application of merchant found", func() {
+		merchantID := uuid.New()
+		owned := getTestApplicationModel()
+		owned.MerchantID = &merchantID
+
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(owned, nil)
+		actual, err := s.service.GetApplication(context.Background(), getTestMerchantPrincipal(merchantID), applicationDTO.ID, "")
+		s.NoError(err)
+		s.Equal(merchantID.String(), actual.MerchantID)
+	})
+
+	s.Run("error occurs because application belongs to another merchant", func() {
+		owned := getTestApplicationModel()
+		owned.MerchantID = lo.ToPtr(uuid.New())
+
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(owned, nil)
+		_, err := s.service.GetApplication(context.Background(), getTestMerchantPrincipal(uuid.New()), applicationDTO.ID, "")
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because application was not submitted by merchant", func() {
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(applicationModel, nil)
+		_, err := s.service.GetApplication(context.Background(), getTestMerchantPrincipal(uuid.New()), applicationDTO.ID, "")
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("application of user found", func() {
+		userID := uuid.New()
+		owned := getTestApplicationModel()
+		owned.UserID = &userID
+
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(owned, nil)
+		actual, err := s.service.GetApplication(context.Background(), getTestUserPrincipal(userID), applicationDTO.ID, "")
+		s.NoError(err)
+		s.Equal(userID.String(), actual.UserID)
+	})
+
+	s.Run("error occurs because application belongs to another user", func() {
+		owned := getTestApplicationModel()
+		owned.UserID = lo.ToPtr(uuid.New())
+
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(owned, nil)
+		_, err := s.service.GetApplication(context.Background(), getTestUserPrincipal(uuid.New()), applicationDTO.ID, "")
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because principal is neither owner nor admin", func() {
+		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(applicationModel, nil)
+		_, err := s.service.GetApplication(context.Background(), auth.Principal{Subject: "anakin"}, applicationDTO.ID, "")
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
 	s.Run("error occurs because application not found", func() {
 		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(models.Application{}, gorm.ErrRecordNotFound)
-		actual, err := s.service.GetApplication(context.Background(), applicationDTO.ID)
-		s.Error(err)
+		actual, err := s.service.GetApplication(context.Background(), getTestAdminPrincipal(), applicationDTO.ID, "")
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
 		s.Equal(dto.ApplicationDTO{}, actual)
-		s.Contains(err.Error(), "not found")
 	})
 
 	s.Run("error occurs while getting application", func() {
 		s.applicationRepository.EXPECT().GetWithProcessedOffers(gomock.Any(), applicationDTO.ID).Return(models.Application{}, errors.New("db error"))
-		actual, err := s.service.GetApplication(context.Background(), applicationDTO.ID)
+		actual, err := s.service.GetApplication(context.Background(), getTestAdminPrincipal(), applicationDTO.ID, "")
 		s.Error(err)
 		s.Equal(dto.ApplicationDTO{}, actual)
 		s.Contains(err.Error(), "db error")
@@ -144,33 +486,39 @@ func (s *applicationServiceTestSuite) Test_UpdateApplicationStatuses() {
 	s.Run("application statuses updated", func() {
 		bankOffer := getTestOfferDTO("bank1")
 		bankOffer.Status = "PROCESSED"
-		updatedOfferModel := getTestOfferModel("bank1")
+		updatedOfferModel := getTestVerifiedOfferModel("bank1")
 		updatedOfferModel.Status = "PROCESSED"
-		offerResponse := getTestOfferResponse()
+		offerResponse := getTestVerifiedOfferResponse("bank1")
 
-		s.offerRepository.EXPECT().List(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
 		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).Return(bankOffer, nil)
-		s.offerRepository.EXPECT().Update(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(nil)
-		s.wsHandler.EXPECT().BroadcastNewOffer(offerModel.ApplicationID.String(), offerResponse)
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(true, nil)
+		s.expectEvents(models.OfferEventStatusChanged, models.OfferEventVerificationFailed)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offerModel.ApplicationID.String(), offerResponse)
+		s.expectEvents(models.OfferEventBroadcast)
 
 		s.service.UpdateApplicationStatuses(context.Background())
 	})
 
-	s.Run("error occurs while listing offers", func() {
-		s.offerRepository.EXPECT().List(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}).Return(nil, errors.New("db error"))
+	s.Run("error occurs while claiming offers", func() {
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(nil, errors.New("db error"))
 
 		s.service.UpdateApplicationStatuses(context.Background())
 	})
 
 	s.Run("error occurs while getting application from bank", func() {
-		s.offerRepository.EXPECT().List(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
 		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).Return(dto.OfferDTO{}, errors.New("bank error"))
 
 		s.service.UpdateApplicationStatuses(context.Background())
 	})
 
 	s.Run("bank application status is the same as offer status", func() {
-		s.offerRepository.EXPECT().List(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
 		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).Return(getTestOfferDTO("bank1"), nil)
 
 		s.service.UpdateApplicationStatuses(context.Background())
@@ -179,15 +527,638 @@ func (s *applicationServiceTestSuite) Test_UpdateApplicationStatuses() {
 	s.Run("error occurs while updating offer", func() {
 		bankOffer := getTestOfferDTO("bank1")
 		bankOffer.Status = "PROCESSED"
-		updatedOfferModel := getTestOfferModel("bank1")
+		updatedOfferModel := getTestVerifiedOfferModel("bank1")
 		updatedOfferModel.Status = "PROCESSED"
 
-		s.offerRepository.EXPECT().List(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
 		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).Return(bankOffer, nil)
-		s.offerRepository.EXPECT().Update(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(errors.New("update error"))
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(false, errors.New("update error"))
 
 		s.service.UpdateApplicationStatuses(context.Background())
 	})
+
+	s.Run("offer quarantined because stated figures diverge", func() {
+		service := s.newService(s.banks)
+		service.cfg.OfferVerification.Quarantine = true
+		bankOffer := getTestOfferDTO("bank1")
+		bankOffer.Status = "PROCESSED"
+		updatedOfferModel := getTestVerifiedOfferModel("bank1")
+		updatedOfferModel.Status = "PROCESSED"
+		updatedOfferModel.Quarantined = true
+
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
+		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).Return(bankOffer, nil)
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(true, nil)
+		s.expectEvents(models.OfferEventStatusChanged, models.OfferEventVerificationFailed)
+
+		service.UpdateApplicationStatuses(context.Background())
+	})
+
+	s.Run("concurrent bank requests limited", func() {
+		var inFlight, maxInFlight atomic.Int32
+		offers := make([]models.Offer, 0, 4)
+		for range 4 {
+			offer := getTestOfferModel("bank1")
+			offer.ID = uuid.New()
+			offers = append(offers, offer)
+		}
+
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offers, nil)
+		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).DoAndReturn(func(context.Context, string) (dto.OfferDTO, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for peak := maxInFlight.Load(); current > peak && !maxInFlight.CompareAndSwap(peak, current); {
+				peak = maxInFlight.Load()
+			}
+			time.Sleep(10 * time.Millisecond)
+			return getTestOfferDTO("bank1"), nil
+		}).Times(4)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), gomock.Any(), 1, gomock.Any()).Return(nil).Times(4)
+
+		s.service.UpdateApplicationStatuses(context.Background())
+		s.Equal(int32(2), maxInFlight.Load())
+	})
+
+	s.Run("offers left for next run after timeout", func() {
+		service := s.newService(s.banks)
+		service.cfg.Queue.PollTimeout = time.Nanosecond
+
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 0, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ int, nextPollAt time.Time) error {
+			s.WithinDuration(time.Now(), nextPollAt, 100*time.Millisecond)
+			return nil
+		})
+
+		service.UpdateApplicationStatuses(context.Background())
+	})
+
+	s.Run("offer answered at run deadline stored", func() {
+		service := s.newService(s.banks)
+		service.cfg.Queue.PollTimeout = 50 * time.Millisecond
+		bankOffer := getTestOfferDTO("bank1")
+		bankOffer.Status = "PROCESSED"
+		updatedOfferModel := getTestVerifiedOfferModel("bank1")
+		updatedOfferModel.Status = "PROCESSED"
+
+		s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return(offerModels, nil)
+		s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
+		s.bank1.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).DoAndReturn(func(ctx context.Context, _ string) (dto.OfferDTO, error) {
+			<-ctx.Done()
+			return bankOffer, nil
+		})
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), updatedOfferModel).DoAndReturn(func(ctx context.Context, _ string, _ models.Offer) (bool, error) {
+			return ctx.Err() == nil, ctx.Err()
+		})
+		s.expectEvents(models.OfferEventStatusChanged, models.OfferEventVerificationFailed)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offerModel.ApplicationID.String(), getTestVerifiedOfferResponse("bank1"))
+		s.expectEvents(models.OfferEventBroadcast)
+
+		service.UpdateApplicationStatuses(context.Background())
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_GetApplicationTimeline() {
+	applicationModel := getTestApplicationModel()
+	id := applicationModel.ID.String()
+
+	s.Run("timeline returned", func() {
+		createdAt := time.Now()
+		offerID := uuid.New()
+		events := []models.OfferEvent{
+			{Type: models.OfferEventSubmitted, Details: "submitted to 2 banks", CreatedAt: createdAt},
+			{Type: models.OfferEventStatusChanged, Bank: "bank1", OfferID: &offerID, Status: "PROCESSED", Details: "DRAFT -> PROCESSED", CreatedAt: createdAt},
+		}
+
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+		s.offerEventRepository.EXPECT().ListByApplication(gomock.Any(), id).Return(events, nil)
+
+		actual, err := s.service.GetApplicationTimeline(context.Background(), getTestAdminPrincipal(), id)
+		s.NoError(err)
+		s.Equal([]dto.OfferEventDTO{
+			{Type: models.OfferEventSubmitted, Details: "submitted to 2 banks", CreatedAt: createdAt},
+			{Type: models.OfferEventStatusChanged, Bank: "bank1", OfferID: offerID.String(), Status: "PROCESSED", Details: "DRAFT -> PROCESSED", CreatedAt: createdAt},
+		}, actual)
+	})
+
+	s.Run("error occurs because application not found", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(models.Application{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.GetApplicationTimeline(context.Background(), getTestAdminPrincipal(), id)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because application belongs to another merchant", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+
+		_, err := s.service.GetApplicationTimeline(context.Background(), getTestMerchantPrincipal(uuid.New()), id)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs while listing events", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+		s.offerEventRepository.EXPECT().ListByApplication(gomock.Any(), id).Return(nil, errors.New("db error"))
+
+		_, err := s.service.GetApplicationTimeline(context.Background(), getTestAdminPrincipal(), id)
+		s.Error(err)
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_GetOfferSchedule() {
+	applicationModel := getTestApplicationModel()
+	id := applicationModel.ID.String()
+	offerID := uuid.New().String()
+
+	s.Run("schedule returned", func() {
+		offer := getTestOfferModel("bank1")
+		offer.Status = models.OfferStatusProcessed
+		offer.MonthlyPaymentAmount = money.MustParse("34")
+
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+		s.offerRepository.EXPECT().GetByApplication(gomock.Any(), id, offerID).Return(offer, nil)
+
+		actual, err := s.service.GetOfferSchedule(context.Background(), getTestAdminPrincipal(), id, offerID)
+		s.NoError(err)
+		s.Len(actual, 3)
+		s.Equal(offer.FirstRepaymentDate, actual[0].DueDate)
+		s.Equal(offer.FirstRepaymentDate.AddDate(0, 2, 0), actual[2].DueDate)
+		s.Zero(actual[2].RemainingBalance)
+	})
+
+	s.Run("error occurs because offer has no terms", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+		s.offerRepository.EXPECT().GetByApplication(gomock.Any(), id, offerID).Return(getTestOfferModel("bank1"), nil)
+
+		_, err := s.service.GetOfferSchedule(context.Background(), getTestAdminPrincipal(), id, offerID)
+		s.ErrorIs(err, ErrNoRepaymentTerms)
+	})
+
+	s.Run("error occurs because offer is quarantined", func() {
+		offer := getTestOfferModel("bank1")
+		offer.Status = models.OfferStatusProcessed
+		offer.Quarantined = true
+
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+		s.offerRepository.EXPECT().GetByApplication(gomock.Any(), id, offerID).Return(offer, nil)
+
+		_, err := s.service.GetOfferSchedule(context.Background(), getTestAdminPrincipal(), id, offerID)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because application belongs to another merchant", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+
+		_, err := s.service.GetOfferSchedule(context.Background(), getTestMerchantPrincipal(uuid.New()), id, offerID)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because offer not found", func() {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), id).Return(applicationModel, nil)
+		s.offerRepository.EXPECT().GetByApplication(gomock.Any(), id, offerID).Return(models.Offer{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.GetOfferSchedule(context.Background(), getTestAdminPrincipal(), id, offerID)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because offer ID is malformed", func() {
+		_, err := s.service.GetOfferSchedule(context.Background(), getTestAdminPrincipal(), id, "anakin")
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_SubmitApplication_MerchantBanks() {
+	merchant := getTestMerchant()
+	application := getTestApplicationDTO()
+	application.MerchantID = merchant.ID.String()
+
+	s.Run("application submitted to banks of merchant", func() {
+		s.merchantRepository.EXPECT().Get(gomock.Any(), application.MerchantID).Return(merchant, nil).Times(2)
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, app *models.Application) error {
+			s.Len(app.Submissions, 1)
+			s.Equal("bank1", app.Submissions[0].Bank)
+			return nil
+		}).Times(2)
+
+		for range 2 {
+			_, err := s.service.SubmitApplication(context.Background(), application)
+			s.NoError(err)
+		}
+
+		s.Require().Len(s.tenantBankConfigs, 1)
+		s.Equal("https://bank1.mos-eisley.test", s.tenantBankConfigs[0].BaseURL)
+		s.Equal(map[string]string{"X-Client": "aggregator", "X-Api-Key": "merchant-secret"}, s.tenantBankConfigs[0].HTTP.Headers)
+		s.Equal(time.Second, s.tenantBankConfigs[0].Polling.InitialInterval)
+	})
+
+	s.Run("bank created again after merchant is updated", func() {
+		updated := merchant
+		updated.UpdatedAt = merchant.UpdatedAt.Add(time.Minute)
+		updated.Banks = []models.MerchantBank{{Name: "bank1", BaseURL: "https://bank1.tatooine.test"}}
+
+		s.merchantRepository.EXPECT().Get(gomock.Any(), application.MerchantID).Return(updated, nil)
+		s.applicationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := s.service.SubmitApplication(context.Background(), application)
+		s.NoError(err)
+		s.Require().Len(s.tenantBankConfigs, 2)
+		s.Equal("https://bank1.tatooine.test", s.tenantBankConfigs[1].BaseURL)
+	})
+
+	s.Run("shared banks used without merchant settings", func() {
+		enabled, err := s.service.merchantBanks(models.Merchant{ID: uuid.New(), Banks: []models.MerchantBank{{Name: "bank2"}}})
+		s.NoError(err)
+		s.Equal(map[string]banks.Bank{"bank2": s.bank2}, enabled)
+
+		enabled, err = s.service.merchantBanks(models.Merchant{ID: uuid.New()})
+		s.NoError(err)
+		s.Len(enabled, 2)
+	})
+
+	s.Run("error occurs because no bank of merchant supports currency", func() {
+		sek := application
+		sek.Currency = "SEK"
+		s.merchantRepository.EXPECT().Get(gomock.Any(), application.MerchantID).Return(merchant, nil)
+
+		_, err := s.service.SubmitApplication(context.Background(), sek)
+		s.ErrorIs(err, ErrUnsupportedCurrency)
+	})
+
+	s.Run("error occurs because merchant not found", func() {
+		s.merchantRepository.EXPECT().Get(gomock.Any(), application.MerchantID).Return(models.Merchant{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.SubmitApplication(context.Background(), application)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_ProcessSubmissions_MerchantBanks() {
+	merchant := getTestMerchant()
+	submission := getTestSubmissionModel("bank1", 0)
+	submission.Application.MerchantID = &merchant.ID
+
+	s.Run("application submitted to bank of merchant", func() {
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchant.ID.String()).Return(merchant, nil)
+		s.tenantBank.EXPECT().SubmitApplication(gomock.Any(), gomock.Any()).Return(getTestOfferDTO("bank1"), nil)
+		s.submissionRepository.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ models.Submission, offer *models.Offer) error {
+			s.Equal(&merchant.ID, offer.MerchantID)
+			return nil
+		})
+		s.expectEvents(models.OfferEventBankResponse, models.OfferEventVerificationFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+
+	s.Run("submission retried because merchant cannot be read", func() {
+		s.submissionRepository.EXPECT().ClaimDue(gomock.Any(), getTestLease(10)).Return([]models.Submission{submission}, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchant.ID.String()).Return(models.Merchant{}, errors.New("db error"))
+		s.submissionRepository.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, actual models.Submission) error {
+			s.Equal(models.SubmissionStatusPending, actual.Status)
+			s.Contains(actual.LastError, "db error")
+			return nil
+		})
+		s.expectEvents(models.OfferEventSubmissionFailed)
+
+		s.service.ProcessSubmissions(context.Background())
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_UpdateApplicationStatuses_MerchantBanks() {
+	merchant := getTestMerchant()
+	offerModel := getTestOfferModel("bank1")
+	offerModel.MerchantID = &merchant.ID
+
+	s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT"}, getTestLease(100)).Return([]models.Offer{offerModel}, nil)
+	s.offerRepository.EXPECT().Reschedule(gomock.Any(), offerModel.ID.String(), 1, gomock.Any()).Return(nil)
+	s.merchantRepository.EXPECT().Get(gomock.Any(), merchant.ID.String()).Return(merchant, nil)
+	s.tenantBank.EXPECT().GetApplication(gomock.Any(), offerModel.ExternalID).Return(getTestOfferDTO("bank1"), nil)
+
+	s.service.UpdateApplicationStatuses(context.Background())
+}
+
+func (s *applicationServiceTestSuite) Test_OfferChangeEvents() {
+	offer := getTestOfferModel("bank1")
+	updated := getTestOfferModel("bank1")
+	updated.Status = models.OfferStatusProcessed
+	updated.AnnualPercentageRate = 12.5
+	updated.NumberOfPayments = 6
+
+	events := offerChangeEvents(offer, updated)
+	s.Len(events, 2)
+	s.Equal(models.OfferEventStatusChanged, events[0].Type)
+	s.Equal("DRAFT -> PROCESSED", events[0].Details)
+	s.Equal(models.OfferEventTermsChanged, events[1].Type)
+	s.Equal("numberOfPayments: 3 -> 6, annualPercentageRate: 10 -> 12.5", events[1].Details)
+
+	s.Empty(offerChangeEvents(offer, offer))
+}
+
+func (s *applicationServiceTestSuite) Test_ExpireOffers() {
+	s.Run("draft offers timed out", func() {
+		offerModel := getTestOfferModel("bank1")
+		offerModel.Status = models.OfferStatusTimedOut
+
+		s.offerRepository.EXPECT().TimeOut(gomock.Any(), "bank1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, createdBefore time.Time) ([]models.Offer, error) {
+			s.WithinDuration(time.Now().Add(-time.Hour), createdBefore, 100*time.Millisecond)
+			return []models.Offer{offerModel}, nil
+		})
+		s.expectEvents(models.OfferEventStatusChanged)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offerModel.ApplicationID.String(), getTestOfferResponse("bank1", "TIMED_OUT"))
+		s.expectEvents(models.OfferEventBroadcast)
+
+		s.service.ExpireOffers(context.Background())
+	})
+
+	s.Run("error occurs while timing out offers", func() {
+		s.offerRepository.EXPECT().TimeOut(gomock.Any(), "bank1", gomock.Any()).Return(nil, errors.New("db error"))
+
+		s.service.ExpireOffers(context.Background())
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_PollInterval() {
+	s.Equal(time.Second, s.service.pollInterval("bank1", 0))
+	s.Equal(2*time.Second, s.service.pollInterval("bank1", 1))
+	s.Equal(8*time.Second, s.service.pollInterval("bank1", 3))
+	s.Equal(10*time.Second, s.service.pollInterval("bank1", 10))
+	s.Equal(time.Duration(0), s.service.pollInterval("bank2", 5))
+
+	s.service.cfg.Banks["bank3"] = config.BankConfig{
+		Polling: config.BankPollingConfig{InitialInterval: time.Second, Multiplier: 2, MaxInterval: 0},
+	}
+	s.Equal(2*time.Second, s.service.pollInterval("bank3", 1))
+	s.Equal(1024*time.Second, s.service.pollInterval("bank3", 10))
+	s.Positive(s.service.pollInterval("bank3", 100))
+}
+
+func (s *applicationServiceTestSuite) Test_HandleBankWebhook() {
+	offerModel := getTestOfferModel("bank3")
+	body := []byte(`{"id":"bank3-offer-1"}`)
+
+	bank3 := webhookBank{mock_banks.NewMockBank(s.ctrl), mock_banks.NewMockWebhookReceiver(s.ctrl)}
+	bank3.MockBank.EXPECT().Name().Return("bank3").AnyTimes()
+	bank3.MockWebhookReceiver.EXPECT().PollingDisabled().Return(true)
+	service := s.newService(append(s.banks, bank3))
+
+	s.Run("offer updated", func() {
+		bankOffer := getTestOfferDTO("bank3")
+		bankOffer.Status = "PROCESSED"
+		updatedOfferModel := getTestVerifiedOfferModel("bank3")
+		updatedOfferModel.Status = "PROCESSED"
+
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", offerModel.ExternalID).Return([]models.Offer{offerModel}, nil)
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(true, nil)
+		s.expectEvents(models.OfferEventStatusChanged, models.OfferEventVerificationFailed)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offerModel.ApplicationID.String(), getTestVerifiedOfferResponse("bank3"))
+		s.expectEvents(models.OfferEventBroadcast)
+
+		s.NoError(service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body))
+	})
+
+	s.Run("revised terms of offer with unchanged status recorded", func() {
+		bankOffer := getTestOfferDTO("bank3")
+		bankOffer.MonthlyPaymentAmount = money.MustParse("55")
+
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", offerModel.ExternalID).Return([]models.Offer{offerModel}, nil)
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, offer models.Offer) (bool, error) {
+				s.Equal(money.MustParse("55"), offer.MonthlyPaymentAmount)
+				return true, nil
+			})
+		s.expectEvents(models.OfferEventTermsChanged, models.OfferEventVerificationFailed)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offerModel.ApplicationID.String(), gomock.Any())
+		s.expectEvents(models.OfferEventBroadcast)
+
+		s.NoError(service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body))
+	})
+
+	s.Run("unchanged offer ignored", func() {
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(getTestOfferDTO("bank3"), nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", offerModel.ExternalID).Return([]models.Offer{offerModel}, nil)
+
+		s.NoError(service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body))
+	})
+
+	s.Run("update of settled offer ignored", func() {
+		for _, status := range []string{"PROCESSED", "DECLINED", "TIMED_OUT"} {
+			settledOfferModel := getTestOfferModel("bank3")
+			settledOfferModel.Status = status
+			bankOffer := getTestOfferDTO("bank3")
+			bankOffer.MonthlyPaymentAmount = money.MustParse("55")
+
+			bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+			s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", offerModel.ExternalID).Return([]models.Offer{settledOfferModel}, nil)
+
+			s.NoError(service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body))
+		}
+	})
+
+	s.Run("update of offer settled meanwhile not recorded", func() {
+		bankOffer := getTestOfferDTO("bank3")
+		bankOffer.Status = "PROCESSED"
+		updatedOfferModel := getTestVerifiedOfferModel("bank3")
+		updatedOfferModel.Status = "PROCESSED"
+
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", offerModel.ExternalID).Return([]models.Offer{offerModel}, nil)
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offerModel.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offerModel.ID.String(), updatedOfferModel).Return(false, nil)
+
+		s.NoError(service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body))
+	})
+
+	s.Run("error occurs because bank is unknown", func() {
+		err := service.HandleBankWebhook(context.Background(), "", "bank4", "signature", body)
+		s.ErrorIs(err, ErrUnknownBank)
+	})
+
+	s.Run("error occurs because bank does not support webhooks", func() {
+		err := service.HandleBankWebhook(context.Background(), "", "bank1", "signature", body)
+		s.ErrorIs(err, banks.ErrWebhooksNotSupported)
+	})
+
+	s.Run("error occurs because signature is invalid", func() {
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(dto.OfferDTO{}, banks.ErrInvalidSignature)
+
+		err := service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body)
+		s.ErrorIs(err, banks.ErrInvalidSignature)
+	})
+
+	s.Run("error occurs because offer not found", func() {
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(getTestOfferDTO("bank3"), nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", offerModel.ExternalID).Return(nil, nil)
+
+		err := service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_HandleBankWebhook_Tenants() {
+	body := []byte(`{"id":"bank3-offer-1"}`)
+	bankOffer := getTestOfferDTO("bank3")
+	bankOffer.Status = "PROCESSED"
+
+	bank3 := webhookBank{mock_banks.NewMockBank(s.ctrl), mock_banks.NewMockWebhookReceiver(s.ctrl)}
+	bank3.MockBank.EXPECT().Name().Return("bank3").AnyTimes()
+	bank3.MockWebhookReceiver.EXPECT().PollingDisabled().Return(false)
+	s.tenantWebhookBank = &webhookBank{mock_banks.NewMockBank(s.ctrl), mock_banks.NewMockWebhookReceiver(s.ctrl)}
+	service := s.newService(append(s.banks, bank3))
+
+	// The merchant has its own account at bank3, which assigns the same IDs as the shared one.
+	merchant := models.Merchant{ID: uuid.New(), Banks: []models.MerchantBank{{Name: "bank3", BaseURL: "https://bank3.example.com"}}}
+	sharedOffer := getTestOfferModel("bank3")
+	sharedOffer.ID = uuid.New()
+	merchantOffer := getTestOfferModel("bank3")
+	merchantOffer.ID = uuid.New()
+	merchantOffer.MerchantID = &merchant.ID
+
+	expectApplied := func(offer models.Offer) {
+		s.applicationRepository.EXPECT().Get(gomock.Any(), offer.ApplicationID.String()).Return(getTestApplicationModel(), nil)
+		s.offerRepository.EXPECT().UpdateDraft(gomock.Any(), offer.ID.String(), gomock.Any()).Return(true, nil)
+		s.expectEvents(models.OfferEventStatusChanged, models.OfferEventVerificationFailed)
+		s.wsHandler.EXPECT().BroadcastNewOffer(gomock.Any(), offer.ApplicationID.String(), gomock.Any())
+		s.expectEvents(models.OfferEventBroadcast)
+	}
+
+	s.Run("webhook of shared account applied to shared offer", func() {
+		bank3.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", sharedOffer.ExternalID).Return([]models.Offer{merchantOffer, sharedOffer}, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchant.ID.String()).Return(merchant, nil)
+		expectApplied(sharedOffer)
+
+		s.NoError(service.HandleBankWebhook(context.Background(), "", "bank3", "signature", body))
+	})
+
+	s.Run("webhook of merchant account applied to offer of merchant", func() {
+		s.tenantWebhookBank.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", sharedOffer.ExternalID).Return([]models.Offer{sharedOffer, merchantOffer}, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchant.ID.String()).Return(merchant, nil).Times(2)
+		expectApplied(merchantOffer)
+
+		s.NoError(service.HandleBankWebhook(context.Background(), merchant.ID.String(), "bank3", "signature", body))
+	})
+
+	s.Run("error occurs because offer belongs to another account", func() {
+		s.tenantWebhookBank.MockWebhookReceiver.EXPECT().ParseWebhook("signature", body).Return(bankOffer, nil)
+		s.offerRepository.EXPECT().ListByExternalID(gomock.Any(), "bank3", sharedOffer.ExternalID).Return([]models.Offer{sharedOffer}, nil)
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchant.ID.String()).Return(merchant, nil)
+
+		err := service.HandleBankWebhook(context.Background(), merchant.ID.String(), "bank3", "signature", body)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because merchant ID is malformed", func() {
+		err := service.HandleBankWebhook(context.Background(), "anakin", "bank3", "signature", body)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *applicationServiceTestSuite) Test_UpdateApplicationStatuses_SkipsWebhookOnlyBanks() {
+	bank3 := webhookBank{mock_banks.NewMockBank(s.ctrl), mock_banks.NewMockWebhookReceiver(s.ctrl)}
+	bank3.MockBank.EXPECT().Name().Return("bank3").AnyTimes()
+	bank3.MockWebhookReceiver.EXPECT().PollingDisabled().Return(true)
+	service := s.newService(append(s.banks, bank3))
+
+	s.offerRepository.EXPECT().Claim(gomock.Any(), repositories.OfferListFilter{Status: "DRAFT", ExcludeBanks: []string{"bank3"}}, getTestLease(100)).Return(nil, nil)
+
+	service.UpdateApplicationStatuses(context.Background())
+}
+
+func (s *applicationServiceTestSuite) expectEvents(eventTypes ...string) {
+	s.offerEventRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, events []models.OfferEvent) error {
+		s.Equal(eventTypes, lo.Map(events, func(e models.OfferEvent, _ int) string { return e.Type }))
+		return nil
+	})
+}
+
+type webhookBank struct {
+	*mock_banks.MockBank
+	*mock_banks.MockWebhookReceiver
+}
+
+func getTestConfig() *config.Config {
+	return &config.Config{
+		Queue: config.QueueConfig{
+			WorkerID:      "worker-1",
+			Lease:         time.Minute,
+			PollBatchSize: 100,
+			PollWorkers:   4,
+			PollTimeout:   time.Second,
+			SubmitTimeout: 30 * time.Second,
+		},
+		OfferVerification: config.OfferVerificationConfig{
+			APRTolerance:            0.5,
+			TotalRepaymentTolerance: 1,
+		},
+		Submissions: config.SubmissionsConfig{
+			BatchSize:      10,
+			MaxAttempts:    3,
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Minute,
+		},
+		Banks: config.Banks{
+			"bank1": {
+				HTTP: config.BankHTTPConfig{Headers: map[string]string{"X-Client": "aggregator"}},
+				Polling: config.BankPollingConfig{
+					InitialInterval: time.Second,
+					Multiplier:      2,
+					MaxInterval:     10 * time.Second,
+					MaxWait:         time.Hour,
+					MaxConcurrency:  2,
+				},
+			},
+			"bank2": {Currencies: []string{"EUR", "SEK"}},
+		},
+	}
+}
+
+// getTestMerchant returns a merchant with its own URL and credentials of bank1 and an
+// unconfigured bank, which is ignored.
+func getTestMerchant() models.Merchant {
+	return models.Merchant{
+		ID:        uuid.New(),
+		UpdatedAt: time.Now(),
+		Name:      "Mos Eisley Motors",
+		Banks: []models.MerchantBank{
+			{Name: "bank1", BaseURL: "https://bank1.mos-eisley.test", Headers: map[string]string{"X-Api-Key": "merchant-secret"}},
+			{Name: "bank3"},
+		},
+	}
+}
+
+func getTestAdminPrincipal() auth.Principal {
+	return auth.Principal{Subject: "obi-wan", Scopes: []string{auth.ScopeAdmin}}
+}
+
+func getTestUserPrincipal(userID uuid.UUID) auth.Principal {
+	return auth.Principal{Subject: userID.String(), Scopes: []string{auth.ScopeUser}}
+}
+
+func getTestMerchantPrincipal(merchantID uuid.UUID) auth.Principal {
+	return auth.Principal{Subject: merchantID.String(), Scopes: []string{auth.ScopeMerchant}}
+}
+
+func getTestLease(limit int) repositories.Lease {
+	return repositories.Lease{Worker: "worker-1", Duration: time.Minute, Limit: limit}
+}
+
+func getTestSubmissionModel(bank string, attempts int) models.Submission {
+	return models.Submission{
+		ID:            uuid.UUID{},
+		ApplicationID: uuid.UUID{},
+		Application:   getTestApplicationModel(),
+		Bank:          bank,
+		Status:        models.SubmissionStatusPending,
+		Attempts:      attempts,
+	}
 }
 
 func getTestApplicationDTO() dto.ApplicationDTO {
@@ -195,9 +1166,10 @@ func getTestApplicationDTO() dto.ApplicationDTO {
 		ID:              uuid.UUID{}.String(),
 		Phone:           "+37122334455",
 		Email:           "anakin@skywalker.com",
-		Amount:          100,
-		MonthlyIncome:   1000,
-		MonthlyExpenses: 100,
+		Amount:          money.MustParse("100"),
+		MonthlyIncome:   money.MustParse("1000"),
+		MonthlyExpenses: money.MustParse("100"),
+		Currency:        "EUR",
 		Offers:          []dto.OfferDTO{},
 	}
 }
@@ -207,9 +1179,10 @@ func getTestApplicationModel() models.Application {
 		ID:              uuid.UUID{},
 		Phone:           "+37122334455",
 		Email:           "anakin@skywalker.com",
-		Amount:          100,
-		MonthlyIncome:   1000,
-		MonthlyExpenses: 100,
+		Amount:          money.MustParse("100"),
+		MonthlyIncome:   money.MustParse("1000"),
+		MonthlyExpenses: money.MustParse("100"),
+		Currency:        "EUR",
 		Offers:          []models.Offer{},
 	}
 }
@@ -219,8 +1192,8 @@ func getTestOfferDTO(bank string) dto.OfferDTO {
 		ExternalID:           bank + "-offer-1",
 		Status:               "DRAFT",
 		Bank:                 bank,
-		MonthlyPaymentAmount: 50,
-		TotalRepaymentAmount: 150,
+		MonthlyPaymentAmount: money.MustParse("50"),
+		TotalRepaymentAmount: money.MustParse("150"),
 		NumberOfPayments:     3,
 		AnnualPercentageRate: 10.0,
 		FirstRepaymentDate:   "2025-01-01",
@@ -234,8 +1207,9 @@ func getTestOfferModel(bank string) models.Offer {
 		ExternalID:           bank + "-offer-1",
 		Status:               "DRAFT",
 		Bank:                 bank,
-		MonthlyPaymentAmount: 50,
-		TotalRepaymentAmount: 150,
+		MonthlyPaymentAmount: money.MustParse("50"),
+		TotalRepaymentAmount: money.MustParse("150"),
+		Currency:             "EUR",
 		NumberOfPayments:     3,
 		AnnualPercentageRate: 10.0,
 		FirstRepaymentDate:   firstRepaymentDate,
@@ -243,10 +1217,32 @@ func getTestOfferModel(bank string) models.Offer {
 	}
 }
 
-func getTestOfferResponse() exchange.OfferResponse {
+// getTestVerifiedOfferModel returns the test offer with figures recomputed for the test
+// application, which do not match the stated ones.
+func getTestVerifiedOfferModel(bank string) models.Offer {
+	offer := getTestOfferModel(bank)
+	offer.ComputedAnnualPercentageRate = 1143.753
+	offer.ComputedTotalRepaymentAmount = money.MustParse("150")
+	offer.Verification = models.OfferVerificationMismatch
+	return offer
+}
+
+func getTestVerifiedOfferResponse(bank string) exchange.OfferResponse {
+	response := getTestOfferResponse(bank, "PROCESSED")
+	response.ComputedAnnualPercentageRate = 1143.753
+	response.ComputedTotalRepaymentAmount = money.MustParse("150")
+	response.Verification = models.OfferVerificationMismatch
+	return response
+}
+
+func getTestOfferResponse(bank, status string) exchange.OfferResponse {
 	return exchange.OfferResponse{
-		MonthlyPaymentAmount: 50,
-		TotalRepaymentAmount: 150,
+		ID:                   uuid.UUID{}.String(),
+		Bank:                 bank,
+		Status:               status,
+		MonthlyPaymentAmount: money.MustParse("50"),
+		TotalRepaymentAmount: money.MustParse("150"),
+		Currency:             "EUR",
 		NumberOfPayments:     3,
 		AnnualPercentageRate: 10.0,
 		FirstRepaymentDate:   "2025-01-01",
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/models"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ExpireOffers times out DRAFT offers of banks that have not answered within their
+// configured maximum wait, so they are no longer polled, and notifies subscribers.
+// Offers of all merchants are expired by the bank config, as merchants override only the
+// base URL and headers of banks, not the polling settings.
+func (s *applicationService) ExpireOffers(ctx context.Context) {
+	for name := range s.banks {
+		maxWait := s.cfg.Banks[name].Polling.MaxWait
+		if maxWait <= 0 {
+			continue
+		}
+
+		offers, err := s.offerRepo.TimeOut(ctx, name, time.Now().Add(-maxWait))
+		if err != nil {
+			s.logger.Error("failed to time out offers", zap.Error(err), zap.String("bank", name))
+			continue
+		}
+
+		for _, offer := range offers {
+			s.logger.Info("offer timed out", zap.String("bank", name), zap.String("id", offer.ID.String()))
+			s.recordEvents(ctx, newOfferEvent(offer, models.OfferEventStatusChanged, offer.Status,
+				fmt.Sprintf("%s -> %s, no answer within %s", models.OfferStatusDraft, offer.Status, maxWait)))
+			s.broadcastOffer(ctx, offer, mapper.MapOfferDTOToResponse(mapper.MapOfferModelToDTO(offer)))
+		}
+	}
+}
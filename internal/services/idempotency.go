@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/repositories"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with the idempotency key is still in progress")
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, subject, key string, request []byte) (*dto.IdempotentResponseDTO, error)
+	Complete(ctx context.Context, subject, key string, response dto.IdempotentResponseDTO)
+	Release(ctx context.Context, subject, key string)
+}
+
+type idempotencyService struct {
+	logger *zap.Logger
+	cfg    config.IdempotencyConfig
+	repo   repositories.IdempotencyKeyRepository
+}
+
+func NewIdempotencyService(logger *zap.Logger, cfg *config.Config, repo repositories.IdempotencyKeyRepository) IdempotencyService {
+	return &idempotencyService{
+		logger: logger,
+		cfg:    cfg.Idempotency,
+		repo:   repo,
+	}
+}
+
+// Begin reserves the key of the subject for the request, which has to be finished with
+// Complete or Release. Keys of different subjects are independent. If the key was already
+// used for the same request, its stored response is returned instead.
+func (s *idempotencyService) Begin(ctx context.Context, subject, key string, request []byte) (*dto.IdempotentResponseDTO, error) {
+	hash := sha256.Sum256(request)
+	requestHash := hex.EncodeToString(hash[:])
+
+	now := time.Now()
+	acquired, err := s.repo.Acquire(ctx, subject, key, requestHash, now.Add(-s.cfg.LockTimeout), now.Add(-s.cfg.TTL))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to acquire idempotency key")
+	}
+	if acquired {
+		return nil, nil
+	}
+
+	stored, err := s.repo.Get(ctx, subject, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The key was released by a failed request in the meantime.
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, errors.Wrap(err, "failed to get idempotency key")
+	}
+
+	if stored.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if stored.StatusCode == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return &dto.IdempotentResponseDTO{StatusCode: stored.StatusCode, Body: stored.ResponseBody}, nil
+}
+
+// Complete stores the response to the request holding the key, so that it is replayed
+// to retries of the request.
+func (s *idempotencyService) Complete(ctx context.Context, subject, key string, response dto.IdempotentResponseDTO) {
+	if err := s.repo.Complete(ctx, subject, key, response.StatusCode, response.Body); err != nil {
+		s.logger.Error("failed to store idempotent response", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// Release frees the key of a failed request, so that the request can be retried.
+func (s *idempotencyService) Release(ctx context.Context, subject, key string) {
+	if err := s.repo.Delete(ctx, subject, key); err != nil {
+		s.logger.Error("failed to release idempotency key", zap.Error(err), zap.String("key", key))
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"financing-aggregator/internal/dto"
+	mock_repositories "financing-aggregator/internal/mocks/repositories"
+	"financing-aggregator/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type idempotencyServiceTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	idempotencyKeyRepository *mock_repositories.MockIdempotencyKeyRepository
+
+	service IdempotencyService
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	suite.Run(t, new(idempotencyServiceTestSuite))
+}
+
+func (s *idempotencyServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.idempotencyKeyRepository = mock_repositories.NewMockIdempotencyKeyRepository(s.ctrl)
+
+	cfg := getTestConfig()
+	cfg.Idempotency.TTL = time.Hour
+	cfg.Idempotency.LockTimeout = time.Minute
+	s.service = NewIdempotencyService(zap.NewNop(), cfg, s.idempotencyKeyRepository)
+}
+
+func (s *idempotencyServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *idempotencyServiceTestSuite) Test_Begin() {
+	request := []byte(`POST /api/applications {"amount": 100}`)
+	hash := getTestRequestHash(request)
+
+	s.Run("key reserved for new request", func() {
+		s.idempotencyKeyRepository.EXPECT().Acquire(gomock.Any(), "merchant-1", "key-1", hash, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _, _ string, lockedBefore, expiredBefore time.Time) (bool, error) {
+				s.WithinDuration(time.Now().Add(-time.Minute), lockedBefore, time.Second)
+				s.WithinDuration(time.Now().Add(-time.Hour), expiredBefore, time.Second)
+				return true, nil
+			})
+
+		actual, err := s.service.Begin(context.Background(), "merchant-1", "key-1", request)
+		s.NoError(err)
+		s.Nil(actual)
+	})
+
+	s.Run("stored response returned for repeated request", func() {
+		s.idempotencyKeyRepository.EXPECT().Acquire(gomock.Any(), "merchant-1", "key-1", hash, gomock.Any(), gomock.Any()).Return(false, nil)
+		s.idempotencyKeyRepository.EXPECT().Get(gomock.Any(), "merchant-1", "key-1").Return(models.IdempotencyKey{
+			Subject:      "merchant-1",
+			Key:          "key-1",
+			RequestHash:  hash,
+			StatusCode:   200,
+			ResponseBody: []byte(`{"id": "1"}`),
+		}, nil)
+
+		actual, err := s.service.Begin(context.Background(), "merchant-1", "key-1", request)
+		s.NoError(err)
+		s.Equal(&dto.IdempotentResponseDTO{StatusCode: 200, Body: []byte(`{"id": "1"}`)}, actual)
+	})
+
+	s.Run("error occurs because key was used with different request", func() {
+		s.idempotencyKeyRepository.EXPECT().Acquire(gomock.Any(), "merchant-1", "key-1", hash, gomock.Any(), gomock.Any()).Return(false, nil)
+		s.idempotencyKeyRepository.EXPECT().Get(gomock.Any(), "merchant-1", "key-1").Return(models.IdempotencyKey{
+			Subject:     "merchant-1",
+			Key:         "key-1",
+			RequestHash: "other",
+			StatusCode:  200,
+		}, nil)
+
+		_, err := s.service.Begin(context.Background(), "merchant-1", "key-1", request)
+		s.ErrorIs(err, ErrIdempotencyKeyReused)
+	})
+
+	s.Run("error occurs because request is in progress", func() {
+		s.idempotencyKeyRepository.EXPECT().Acquire(gomock.Any(), "merchant-1", "key-1", hash, gomock.Any(), gomock.Any()).Return(false, nil)
+		s.idempotencyKeyRepository.EXPECT().Get(gomock.Any(), "merchant-1", "key-1").Return(models.IdempotencyKey{
+			Subject:     "merchant-1",
+			Key:         "key-1",
+			RequestHash: hash,
+		}, nil)
+
+		_, err := s.service.Begin(context.Background(), "merchant-1", "key-1", request)
+		s.ErrorIs(err, ErrIdempotencyKeyInProgress)
+	})
+
+	s.Run("error occurs because key was released in the meantime", func() {
+		s.idempotencyKeyRepository.EXPECT().Acquire(gomock.Any(), "merchant-1", "key-1", hash, gomock.Any(), gomock.Any()).Return(false, nil)
+		s.idempotencyKeyRepository.EXPECT().Get(gomock.Any(), "merchant-1", "key-1").Return(models.IdempotencyKey{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.Begin(context.Background(), "merchant-1", "key-1", request)
+		s.ErrorIs(err, ErrIdempotencyKeyInProgress)
+	})
+
+	s.Run("error occurs while acquiring key", func() {
+		s.idempotencyKeyRepository.EXPECT().Acquire(gomock.Any(), "merchant-1", "key-1", hash, gomock.Any(), gomock.Any()).Return(false, errors.New("db error"))
+
+		_, err := s.service.Begin(context.Background(), "merchant-1", "key-1", request)
+		s.ErrorContains(err, "db error")
+	})
+}
+
+func (s *idempotencyServiceTestSuite) Test_CompleteAndRelease() {
+	s.idempotencyKeyRepository.EXPECT().Complete(gomock.Any(), "merchant-1", "key-1", 200, []byte(`{}`)).Return(nil)
+	s.service.Complete(context.Background(), "merchant-1", "key-1", dto.IdempotentResponseDTO{StatusCode: 200, Body: []byte(`{}`)})
+
+	s.idempotencyKeyRepository.EXPECT().Delete(gomock.Any(), "merchant-1", "key-2").Return(errors.New("db error"))
+	s.service.Release(context.Background(), "merchant-1", "key-2")
+}
+
+func getTestRequestHash(request []byte) string {
+	hash := sha256.Sum256(request)
+	return hex.EncodeToString(hash[:])
+}
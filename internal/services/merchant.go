@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyPrefix       = "fak_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	ErrQuotaExceeded = errors.New("API key quota exceeded")
+)
+
+type MerchantService interface {
+	CreateMerchant(ctx context.Context, name string) (dto.MerchantDTO, error)
+	GetMerchant(ctx context.Context, id string) (dto.MerchantDTO, error)
+	UpdateSettings(ctx context.Context, id string, settings dto.MerchantSettingsDTO) (dto.MerchantDTO, error)
+	CreateAPIKey(ctx context.Context, merchantID string, requestQuota, applicationQuota int) (dto.APIKeyDTO, error)
+	RevokeAPIKey(ctx context.Context, merchantID, keyID string) error
+	Authenticate(ctx context.Context, key string) (auth.Principal, error)
+	ConsumeApplicationQuota(ctx context.Context, keyID string) (time.Time, error)
+	RefundApplicationQuota(ctx context.Context, keyID string, windowStart time.Time)
+	PurgeQuotaUsage(ctx context.Context)
+}
+
+type merchantService struct {
+	logger *zap.Logger
+	cfg    config.MerchantsConfig
+	banks  config.Banks
+	repo   repositories.MerchantRepository
+}
+
+func NewMerchantService(logger *zap.Logger, cfg *config.Config, repo repositories.MerchantRepository) MerchantService {
+	return &merchantService{
+		logger: logger,
+		cfg:    cfg.Merchants,
+		banks:  cfg.Banks,
+		repo:   repo,
+	}
+}
+
+func (s *merchantService) CreateMerchant(ctx context.Context, name string) (dto.MerchantDTO, error) {
+	merchant := models.Merchant{Name: name}
+	if err := s.repo.Create(ctx, &merchant); err != nil {
+		return dto.MerchantDTO{}, errors.Wrap(err, "failed to create merchant")
+	}
+	return mapper.MapMerchantModelToDTO(merchant), nil
+}
+
+func (s *merchantService) GetMerchant(ctx context.Context, id string) (dto.MerchantDTO, error) {
+	if uuid.Validate(id) != nil {
+		return dto.MerchantDTO{}, errors.Wrap(gorm.ErrRecordNotFound, "invalid merchant ID")
+	}
+
+	merchant, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return dto.MerchantDTO{}, errors.Wrap(err, "failed to get merchant")
+	}
+	return mapper.MapMerchantModelToDTO(merchant), nil
+}
+
+// UpdateSettings replaces banks and branding of the merchant. Only configured banks can be
+// enabled, failing with ErrUnknownBank otherwise.
+func (s *merchantService) UpdateSettings(ctx context.Context, id string, settings dto.MerchantSettingsDTO) (dto.MerchantDTO, error) {
+	for _, bank := range settings.Banks {
+		if _, ok := s.banks[bank.Name]; !ok {
+			return dto.MerchantDTO{}, errors.Wrap(ErrUnknownBank, bank.Name)
+		}
+	}
+	if uuid.Validate(id) != nil {
+		return dto.MerchantDTO{}, errors.Wrap(gorm.ErrRecordNotFound, "invalid merchant ID")
+	}
+
+	merchant, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return dto.MerchantDTO{}, errors.Wrap(err, "failed to get merchant")
+	}
+
+	mapper.ApplyMerchantSettingsToModel(settings, &merchant)
+	if err := s.repo.UpdateSettings(ctx, &merchant); err != nil {
+		return dto.MerchantDTO{}, errors.Wrap(err, "failed to update merchant settings")
+	}
+
+	s.logger.Info("merchant settings updated", zap.String("merchantID", id), zap.Int("banks", len(merchant.Banks)))
+	return mapper.MapMerchantModelToDTO(merchant), nil
+}
+
+// CreateAPIKey generates a key of the merchant. The key itself is returned only here, just
+// its hash is stored.
+func (s *merchantService) CreateAPIKey(ctx context.Context, merchantID string, requestQuota, applicationQuota int) (dto.APIKeyDTO, error) {
+	id, err := uuid.Parse(merchantID)
+	if err != nil {
+		return dto.APIKeyDTO{}, errors.Wrap(gorm.ErrRecordNotFound, "invalid merchant ID")
+	}
+	if _, err := s.repo.Get(ctx, merchantID); err != nil {
+		return dto.APIKeyDTO{}, errors.Wrap(err, "failed to get merchant")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return dto.APIKeyDTO{}, errors.Wrap(err, "failed to generate API key")
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	model := models.MerchantAPIKey{
+		MerchantID:       id,
+		Prefix:           key[:apiKeyPrefixLength],
+		KeyHash:          hashAPIKey(key),
+		RequestQuota:     requestQuota,
+		ApplicationQuota: applicationQuota,
+	}
+	if err := s.repo.CreateAPIKey(ctx, &model); err != nil {
+		return dto.APIKeyDTO{}, errors.Wrap(err, "failed to create API key")
+	}
+
+	s.logger.Info("API key created", zap.String("merchantID", merchantID), zap.String("prefix", model.Prefix))
+	apiKey := mapper.MapAPIKeyModelToDTO(model)
+	apiKey.Key = key
+	return apiKey, nil
+}
+
+func (s *merchantService) RevokeAPIKey(ctx context.Context, merchantID, keyID string) error {
+	if uuid.Validate(merchantID) != nil || uuid.Validate(keyID) != nil {
+		return errors.Wrap(gorm.ErrRecordNotFound, "invalid API key ID")
+	}
+
+	revoked, err := s.repo.RevokeAPIKey(ctx, merchantID, keyID)
+	if err != nil {
+		return errors.Wrap(err, "failed to revoke API key")
+	}
+	if !revoked {
+		return errors.Wrap(gorm.ErrRecordNotFound, "API key not found")
+	}
+	return nil
+}
+
+// Authenticate returns the merchant the key belongs to, counting the request against the
+// request quota of the key.
+func (s *merchantService) Authenticate(ctx context.Context, key string) (auth.Principal, error) {
+	apiKey, err := s.repo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return auth.Principal{}, ErrInvalidAPIKey
+		}
+		return auth.Principal{}, errors.Wrap(err, "failed to get API key")
+	}
+
+	if _, err := s.consume(ctx, apiKey, models.QuotaKindRequests, apiKey.RequestQuota, s.cfg.RequestQuotaWindow); err != nil {
+		return auth.Principal{}, err
+	}
+
+	return auth.Principal{
+		Subject:  apiKey.MerchantID.String(),
+		Scopes:   []string{auth.ScopeMerchant},
+		APIKeyID: apiKey.ID.String(),
+	}, nil
+}
+
+// ConsumeApplicationQuota counts an application against the application quota of the key and
+// returns the start of the window it was counted in, zero if the key has no application quota.
+func (s *merchantService) ConsumeApplicationQuota(ctx context.Context, keyID string) (time.Time, error) {
+	apiKey, err := s.repo.GetAPIKey(ctx, keyID)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get API key")
+	}
+	return s.consume(ctx, apiKey, models.QuotaKindApplications, apiKey.ApplicationQuota, s.cfg.ApplicationQuotaWindow)
+}
+
+// RefundApplicationQuota takes back an application counted by ConsumeApplicationQuota in the
+// window, when no application was created.
+func (s *merchantService) RefundApplicationQuota(ctx context.Context, keyID string, windowStart time.Time) {
+	if windowStart.IsZero() {
+		return
+	}
+
+	if err := s.repo.DecrementUsage(ctx, keyID, models.QuotaKindApplications, windowStart); err != nil {
+		s.logger.Error("failed to refund application quota", zap.String("apiKeyID", keyID), zap.Error(err))
+	}
+}
+
+// consume counts a use of the key in the current quota window and returns the window start,
+// failing with ErrQuotaExceeded once the limit is reached.
+func (s *merchantService) consume(ctx context.Context, apiKey models.MerchantAPIKey, kind string, limit int, window time.Duration) (time.Time, error) {
+	if limit <= 0 || window <= 0 {
+		return time.Time{}, nil
+	}
+
+	windowStart := time.Now().Truncate(window)
+	counted, err := s.repo.IncrementUsage(ctx, apiKey.ID.String(), kind, windowStart, limit)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to count API key usage")
+	}
+	if !counted {
+		return time.Time{}, errors.Wrapf(ErrQuotaExceeded, "%d %s per %s", limit, strings.ToLower(kind), window)
+	}
+	return windowStart, nil
+}
+
+// PurgeQuotaUsage deletes usage counts of quota windows that have ended.
+func (s *merchantService) PurgeQuotaUsage(ctx context.Context) {
+	window := max(s.cfg.RequestQuotaWindow, s.cfg.ApplicationQuotaWindow)
+	deleted, err := s.repo.DeleteUsageBefore(ctx, time.Now().Add(-window))
+	if err != nil {
+		s.logger.Error("failed to purge API key usage", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("purged API key usage", zap.Int64("windows", deleted))
+	}
+}
+
+func hashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/dto"
+	mock_repositories "financing-aggregator/internal/mocks/repositories"
+	"financing-aggregator/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+	"time"
+)
+
+type merchantServiceTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	merchantRepository *mock_repositories.MockMerchantRepository
+
+	service MerchantService
+}
+
+func TestMerchantSuite(t *testing.T) {
+	suite.Run(t, new(merchantServiceTestSuite))
+}
+
+func (s *merchantServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.merchantRepository = mock_repositories.NewMockMerchantRepository(s.ctrl)
+
+	cfg := getTestConfig()
+	cfg.Merchants.RequestQuotaWindow = time.Minute
+	cfg.Merchants.ApplicationQuotaWindow = 24 * time.Hour
+	s.service = NewMerchantService(zap.NewNop(), cfg, s.merchantRepository)
+}
+
+func (s *merchantServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *merchantServiceTestSuite) Test_CreateAPIKey() {
+	merchantID := uuid.NewString()
+
+	s.Run("key created with hash stored", func() {
+		var stored models.MerchantAPIKey
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID).Return(models.Merchant{}, nil)
+		s.merchantRepository.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key *models.MerchantAPIKey) error {
+				key.ID = uuid.New()
+				stored = *key
+				return nil
+			})
+
+		actual, err := s.service.CreateAPIKey(context.Background(), merchantID, 100, 10)
+		s.NoError(err)
+		s.True(strings.HasPrefix(actual.Key, apiKeyPrefix))
+		s.Equal(actual.Key[:apiKeyPrefixLength], actual.Prefix)
+		s.Equal(hashAPIKey(actual.Key), stored.KeyHash)
+		s.Equal(merchantID, stored.MerchantID.String())
+		s.Equal(100, stored.RequestQuota)
+		s.Equal(10, stored.ApplicationQuota)
+	})
+
+	s.Run("error occurs because merchant does not exist", func() {
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID).Return(models.Merchant{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.CreateAPIKey(context.Background(), merchantID, 0, 0)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	s.Run("error occurs because merchant ID is malformed", func() {
+		_, err := s.service.CreateAPIKey(context.Background(), "not-a-uuid", 0, 0)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *merchantServiceTestSuite) Test_UpdateSettings() {
+	merchantID := uuid.New()
+	settings := dto.MerchantSettingsDTO{
+		Banks:    []dto.MerchantBankDTO{{Name: "bank1", BaseURL: "https://bank1.mos-eisley.test", Headers: map[string]string{"X-Api-Key": "secret"}}},
+		Branding: dto.BrandingDTO{DisplayName: "Mos Eisley Motors", PrimaryColor: "#c2b280"},
+	}
+
+	s.Run("settings stored", func() {
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID.String()).Return(models.Merchant{ID: merchantID, Name: "Mos Eisley"}, nil)
+		s.merchantRepository.EXPECT().UpdateSettings(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, merchant *models.Merchant) error {
+				s.Equal([]models.MerchantBank{{Name: "bank1", BaseURL: "https://bank1.mos-eisley.test", Headers: map[string]string{"X-Api-Key": "secret"}}}, merchant.Banks)
+				s.Equal(models.MerchantBranding{DisplayName: "Mos Eisley Motors", PrimaryColor: "#c2b280"}, merchant.Branding)
+				return nil
+			})
+
+		actual, err := s.service.UpdateSettings(context.Background(), merchantID.String(), settings)
+		s.NoError(err)
+		s.Equal(settings.Banks, actual.Banks)
+		s.Equal(settings.Branding, actual.Branding)
+	})
+
+	s.Run("error occurs because bank is unknown", func() {
+		unknown := dto.MerchantSettingsDTO{Banks: []dto.MerchantBankDTO{{Name: "bank3"}}}
+
+		_, err := s.service.UpdateSettings(context.Background(), merchantID.String(), unknown)
+		s.ErrorIs(err, ErrUnknownBank)
+	})
+
+	s.Run("error occurs because merchant does not exist", func() {
+		s.merchantRepository.EXPECT().Get(gomock.Any(), merchantID.String()).Return(models.Merchant{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.UpdateSettings(context.Background(), merchantID.String(), settings)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *merchantServiceTestSuite) Test_Authenticate() {
+	apiKey := getTestAPIKey()
+
+	s.Run("merchant returned for valid key", func() {
+		s.merchantRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("fak_key")).Return(apiKey, nil)
+		s.merchantRepository.EXPECT().IncrementUsage(gomock.Any(), apiKey.ID.String(), models.QuotaKindRequests, gomock.Any(), 100).
+			DoAndReturn(func(_ context.Context, _, _ string, windowStart time.Time, _ int) (bool, error) {
+				s.Equal(windowStart.Truncate(time.Minute), windowStart)
+				s.WithinDuration(time.Now(), windowStart, time.Minute)
+				return true, nil
+			})
+
+		actual, err := s.service.Authenticate(context.Background(), "fak_key")
+		s.NoError(err)
+		s.Equal(auth.Principal{
+			Subject:  apiKey.MerchantID.String(),
+			Scopes:   []string{auth.ScopeMerchant},
+			APIKeyID: apiKey.ID.String(),
+		}, actual)
+	})
+
+	s.Run("usage not counted without quota", func() {
+		unlimited := getTestAPIKey()
+		unlimited.RequestQuota = 0
+		s.merchantRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("fak_key")).Return(unlimited, nil)
+
+		_, err := s.service.Authenticate(context.Background(), "fak_key")
+		s.NoError(err)
+	})
+
+	s.Run("error occurs because key is unknown", func() {
+		s.merchantRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("fak_key")).Return(models.MerchantAPIKey{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.Authenticate(context.Background(), "fak_key")
+		s.ErrorIs(err, ErrInvalidAPIKey)
+	})
+
+	s.Run("error occurs because request quota is exceeded", func() {
+		s.merchantRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("fak_key")).Return(apiKey, nil)
+		s.merchantRepository.EXPECT().IncrementUsage(gomock.Any(), apiKey.ID.String(), models.QuotaKindRequests, gomock.Any(), 100).Return(false, nil)
+
+		_, err := s.service.Authenticate(context.Background(), "fak_key")
+		s.ErrorIs(err, ErrQuotaExceeded)
+	})
+}
+
+func (s *merchantServiceTestSuite) Test_ConsumeApplicationQuota() {
+	apiKey := getTestAPIKey()
+
+	s.Run("application counted", func() {
+		s.merchantRepository.EXPECT().GetAPIKey(gomock.Any(), apiKey.ID.String()).Return(apiKey, nil)
+		s.merchantRepository.EXPECT().IncrementUsage(gomock.Any(), apiKey.ID.String(), models.QuotaKindApplications, gomock.Any(), 10).
+			DoAndReturn(func(_ context.Context, _, _ string, windowStart time.Time, _ int) (bool, error) {
+				s.Equal(windowStart.Truncate(24*time.Hour), windowStart)
+				s.WithinDuration(time.Now(), windowStart, 24*time.Hour)
+				return true, nil
+			})
+
+		windowStart, err := s.service.ConsumeApplicationQuota(context.Background(), apiKey.ID.String())
+		s.NoError(err)
+		s.False(windowStart.IsZero())
+	})
+
+	s.Run("error occurs because application quota is exceeded", func() {
+		s.merchantRepository.EXPECT().GetAPIKey(gomock.Any(), apiKey.ID.String()).Return(apiKey, nil)
+		s.merchantRepository.EXPECT().IncrementUsage(gomock.Any(), apiKey.ID.String(), models.QuotaKindApplications, gomock.Any(), 10).Return(false, nil)
+
+		_, err := s.service.ConsumeApplicationQuota(context.Background(), apiKey.ID.String())
+		s.ErrorIs(err, ErrQuotaExceeded)
+	})
+}
+
+func (s *merchantServiceTestSuite) Test_RefundApplicationQuota() {
+	keyID := uuid.NewString()
+
+	s.Run("application taken back from window", func() {
+		windowStart := time.Now().Truncate(24 * time.Hour)
+		s.merchantRepository.EXPECT().DecrementUsage(gomock.Any(), keyID, models.QuotaKindApplications, windowStart).Return(nil)
+
+		s.service.RefundApplicationQuota(context.Background(), keyID, windowStart)
+	})
+
+	s.Run("nothing refunded without quota", func() {
+		s.service.RefundApplicationQuota(context.Background(), keyID, time.Time{})
+	})
+}
+
+func (s *merchantServiceTestSuite) Test_PurgeQuotaUsage() {
+	s.merchantRepository.EXPECT().DeleteUsageBefore(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, windowStart time.Time) (int64, error) {
+			s.WithinDuration(time.Now().Add(-24*time.Hour), windowStart, time.Second)
+			return 3, nil
+		})
+
+	s.service.PurgeQuotaUsage(context.Background())
+}
+
+func getTestAPIKey() models.MerchantAPIKey {
+	return models.MerchantAPIKey{
+		ID:               uuid.New(),
+		MerchantID:       uuid.New(),
+		Prefix:           "fak_abcdefgh",
+		KeyHash:          hashAPIKey("fak_key"),
+		RequestQuota:     100,
+		ApplicationQuota: 10,
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/amortization"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+var ErrNoRepaymentTerms = errors.New("offer has no repayment terms")
+
+// GetOfferSchedule derives the repayment schedule of a processed offer of the application
+// from the borrowed amount and the offer terms.
+func (s *applicationService) GetOfferSchedule(ctx context.Context, principal auth.Principal, applicationID, offerID string) ([]dto.InstallmentDTO, error) {
+	if uuid.Validate(applicationID) != nil || uuid.Validate(offerID) != nil {
+		return nil, errors.Wrap(gorm.ErrRecordNotFound, "invalid application or offer ID")
+	}
+
+	application, err := s.applicationRepo.Get(ctx, applicationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get application")
+	}
+	if err := authorizeApplication(principal, application); err != nil {
+		return nil, err
+	}
+
+	offer, err := s.offerRepo.GetByApplication(ctx, applicationID, offerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get offer")
+	}
+	if offer.Quarantined {
+		return nil, errors.Wrap(gorm.ErrRecordNotFound, "offer is quarantined")
+	}
+	if offer.Status != models.OfferStatusProcessed || offer.NumberOfPayments == 0 {
+		return nil, ErrNoRepaymentTerms
+	}
+
+	return amortization.Schedule(application.Amount, offer.MonthlyPaymentAmount, offer.NumberOfPayments, offer.FirstRepaymentDate), nil
+}
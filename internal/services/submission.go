@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/models"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ProcessSubmissions sends due pending submissions claimed by this worker to their banks.
+// Failed attempts are retried with exponential backoff until the configured number of
+// attempts is reached. The run ends before the lease does, so that no other worker claims
+// a submission while it is being sent, and submissions not sent by then are released.
+func (s *applicationService) ProcessSubmissions(ctx context.Context) {
+	submissions, err := s.submissionRepo.ClaimDue(ctx, s.lease(s.cfg.Submissions.BatchSize))
+	if err != nil {
+		s.logger.Error("failed to claim due submissions", zap.Error(err))
+		return
+	}
+
+	submitCtx := ctx
+	if s.cfg.Queue.SubmitTimeout > 0 {
+		var cancel context.CancelFunc
+		submitCtx, cancel = context.WithTimeout(ctx, s.cfg.Queue.SubmitTimeout)
+		defer cancel()
+	}
+
+	for _, submission := range submissions {
+		if submitCtx.Err() != nil {
+			if err := s.submissionRepo.Update(ctx, submission); err != nil {
+				s.logger.Error("failed to release submission", zap.Error(err), zap.String("id", submission.ID.String()))
+			}
+			continue
+		}
+		s.processSubmission(ctx, submitCtx, submission)
+	}
+}
+
+// processSubmission sends the submission to the bank within the run deadline of submitCtx
+// and stores the result with ctx.
+func (s *applicationService) processSubmission(ctx, submitCtx context.Context, submission models.Submission) {
+	logger := s.logger.With(zap.String("bank", submission.Bank), zap.String("id", submission.ApplicationID.String()))
+	submission.Attempts++
+
+	available, err := s.banksFor(ctx, submission.Application.MerchantID)
+	if err != nil {
+		s.retrySubmission(ctx, logger, submission, err)
+		return
+	}
+
+	bank, ok := available[submission.Bank]
+	if !ok {
+		logger.Error("submission belongs to unknown bank")
+		submission.Status = models.SubmissionStatusFailed
+		submission.LastError = ErrUnknownBank.Error()
+		if err := s.submissionRepo.Update(ctx, submission); err != nil {
+			logger.Error("failed to update submission", zap.Error(err))
+		}
+		s.recordEvents(ctx, newSubmissionEvent(submission))
+		return
+	}
+
+	offer, err := bank.SubmitApplication(submitCtx, mapper.MapApplicationModelToDTO(submission.Application))
+	if err == nil {
+		offerModel := mapper.MapOfferDTOToModel(offer, submission.ApplicationID)
+		offerModel.MerchantID = submission.Application.MerchantID
+		offerModel.Currency = submission.Application.Currency
+		offerModel.NextPollAt = time.Now().Add(s.pollInterval(submission.Bank, 0))
+		verificationEvents := s.verifyOffer(&offerModel, submission.Application.Amount)
+		submission.LastError = ""
+		if err = s.submissionRepo.Complete(ctx, submission, &offerModel); err == nil {
+			for i := range verificationEvents {
+				verificationEvents[i].OfferID = &offerModel.ID
+			}
+			s.recordEvents(ctx, append([]models.OfferEvent{newOfferEvent(offerModel, models.OfferEventBankResponse, offerModel.Status,
+				fmt.Sprintf("bank application %s", offerModel.ExternalID))}, verificationEvents...)...)
+			return
+		}
+	}
+
+	s.retrySubmission(ctx, logger, submission, err)
+}
+
+// retrySubmission records the failed attempt and schedules the next one, or marks the
+// submission as failed once the configured number of attempts is reached.
+func (s *applicationService) retrySubmission(ctx context.Context, logger *zap.Logger, submission models.Submission, err error) {
+	logger.Error("failed to submit application", zap.Error(err), zap.Int("attempt", submission.Attempts))
+	submission.LastError = err.Error()
+	if submission.Attempts >= s.cfg.Submissions.MaxAttempts {
+		submission.Status = models.SubmissionStatusFailed
+	} else {
+		submission.NextAttemptAt = time.Now().Add(s.submissionBackoff(submission.Attempts))
+	}
+
+	if err := s.submissionRepo.Update(ctx, submission); err != nil {
+		logger.Error("failed to update submission", zap.Error(err))
+	}
+	s.recordEvents(ctx, newSubmissionEvent(submission))
+}
+
+func newSubmissionEvent(submission models.Submission) models.OfferEvent {
+	return models.OfferEvent{
+		ApplicationID: submission.ApplicationID,
+		Bank:          submission.Bank,
+		Type:          models.OfferEventSubmissionFailed,
+		Status:        submission.Status,
+		Details:       fmt.Sprintf("attempt %d: %s", submission.Attempts, submission.LastError),
+	}
+}
+
+func (s *applicationService) submissionBackoff(attempts int) time.Duration {
+	backoff := s.cfg.Submissions.InitialBackoff
+	for i := 1; i < attempts && backoff < s.cfg.Submissions.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.cfg.Submissions.MaxBackoff)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/banks"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/models"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// tenantBanks caches banks created for merchants overriding bank settings. Entries are
+// replaced when the merchant is updated.
+type tenantBanks struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]tenantBanksEntry
+}
+
+type tenantBanksEntry struct {
+	updatedAt time.Time
+	banks     map[string]banks.Bank
+}
+
+// banksFor returns banks of the merchant applications are routed to, all configured banks
+// if there is no merchant.
+func (s *applicationService) banksFor(ctx context.Context, merchantID *uuid.UUID) (map[string]banks.Bank, error) {
+	if merchantID == nil {
+		return s.banks, nil
+	}
+
+	merchant, err := s.merchantRepo.Get(ctx, merchantID.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get merchant")
+	}
+	return s.merchantBanks(merchant)
+}
+
+// merchantBanks returns the configured banks enabled for the merchant. Banks with a base URL
+// or headers of the merchant are created with them, the shared banks are used otherwise.
+// Banks no longer configured are left out.
+func (s *applicationService) merchantBanks(merchant models.Merchant) (map[string]banks.Bank, error) {
+	if len(merchant.Banks) == 0 {
+		return s.banks, nil
+	}
+
+	s.tenantBanks.mu.Lock()
+	defer s.tenantBanks.mu.Unlock()
+	if entry, ok := s.tenantBanks.entries[merchant.ID]; ok && entry.updatedAt.Equal(merchant.UpdatedAt) {
+		return entry.banks, nil
+	}
+
+	enabled := make(map[string]banks.Bank, len(merchant.Banks))
+	for _, merchantBank := range merchant.Banks {
+		bank, ok := s.banks[merchantBank.Name]
+		if !ok {
+			continue
+		}
+
+		if merchantBank.BaseURL != "" || len(merchantBank.Headers) > 0 {
+			var err error
+			bank, err = s.newBank(merchantBank.Name, merchantBankConfig(s.cfg.Banks[merchantBank.Name], merchantBank))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create bank %s of merchant %s", merchantBank.Name, merchant.ID)
+			}
+		}
+		enabled[merchantBank.Name] = bank
+	}
+
+	s.tenantBanks.entries[merchant.ID] = tenantBanksEntry{updatedAt: merchant.UpdatedAt, banks: enabled}
+	return enabled, nil
+}
+
+// merchantBankConfig overrides the bank config with the base URL and headers of the merchant.
+func merchantBankConfig(cfg config.BankConfig, bank models.MerchantBank) config.BankConfig {
+	if bank.BaseURL != "" {
+		cfg.BaseURL = bank.BaseURL
+	}
+
+	headers := make(map[string]string, len(cfg.HTTP.Headers)+len(bank.Headers))
+	maps.Copy(headers, cfg.HTTP.Headers)
+	maps.Copy(headers, bank.Headers)
+	cfg.HTTP.Headers = headers
+	return cfg
+}
+
+// authorizeApplication fails with gorm.ErrRecordNotFound unless the principal owns the
+// application or is an admin, so that applications of others cannot be told from missing ones.
+func authorizeApplication(principal auth.Principal, application models.Application) error {
+	if !principal.CanAccess(application.MerchantID, application.UserID) {
+		return errors.Wrap(gorm.ErrRecordNotFound, "application not found")
+	}
+	return nil
+}
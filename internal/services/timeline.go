@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GetApplicationTimeline returns everything that happened to the application in
+// chronological order: submission to banks, bank responses, offer changes and broadcasts.
+func (s *applicationService) GetApplicationTimeline(ctx context.Context, principal auth.Principal, id string) ([]dto.OfferEventDTO, error) {
+	if uuid.Validate(id) != nil {
+		return nil, errors.Wrap(gorm.ErrRecordNotFound, "invalid application ID")
+	}
+
+	application, err := s.applicationRepo.Get(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get application")
+	}
+	if err := authorizeApplication(principal, application); err != nil {
+		return nil, err
+	}
+
+	events, err := s.offerEventRepo.ListByApplication(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list application events")
+	}
+
+	timeline := make([]dto.OfferEventDTO, 0, len(events))
+	for _, e := range events {
+		timeline = append(timeline, mapper.MapOfferEventModelToDTO(e))
+	}
+	return timeline, nil
+}
+
+// recordEvents stores timeline events. The timeline is informational, so failures are
+// only logged and never interrupt processing.
+func (s *applicationService) recordEvents(ctx context.Context, events ...models.OfferEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if err := s.offerEventRepo.Create(ctx, events); err != nil {
+		s.logger.Error("failed to record offer events", zap.Error(err), zap.String("type", events[0].Type))
+	}
+}
+
+// offerChangeEvents describes how the stored offer differs from its new state.
+func offerChangeEvents(offer, updated models.Offer) []models.OfferEvent {
+	var events []models.OfferEvent
+	if offer.Status != updated.Status {
+		events = append(events, newOfferEvent(offer, models.OfferEventStatusChanged, updated.Status,
+			fmt.Sprintf("%s -> %s", offer.Status, updated.Status)))
+	}
+
+	if changes := termChanges(offer, updated); len(changes) > 0 {
+		events = append(events, newOfferEvent(offer, models.OfferEventTermsChanged, updated.Status,
+			strings.Join(changes, ", ")))
+	}
+	return events
+}
+
+func termChanges(offer, updated models.Offer) []string {
+	var changes []string
+	addChange := func(name string, from, to any) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, from, to))
+		}
+	}
+
+	addChange("monthlyPaymentAmount", offer.MonthlyPaymentAmount, updated.MonthlyPaymentAmount)
+	addChange("totalRepaymentAmount", offer.TotalRepaymentAmount, updated.TotalRepaymentAmount)
+	addChange("numberOfPayments", offer.NumberOfPayments, updated.NumberOfPayments)
+	addChange("annualPercentageRate", offer.AnnualPercentageRate, updated.AnnualPercentageRate)
+	addChange("firstRepaymentDate", offer.FirstRepaymentDate.Format(time.DateOnly), updated.FirstRepaymentDate.Format(time.DateOnly))
+	return changes
+}
+
+func newOfferEvent(offer models.Offer, eventType, status, details string) models.OfferEvent {
+	return models.OfferEvent{
+		ApplicationID: offer.ApplicationID,
+		OfferID:       &offer.ID,
+		Bank:          offer.Bank,
+		Type:          eventType,
+		Status:        status,
+		Details:       details,
+	}
+}
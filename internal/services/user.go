@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/repositories"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+)
+
+// dummyPasswordHash is compared against on logins with an unknown email, so that they take
+// as long as logins with a wrong password and do not reveal registered emails.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+type UserService interface {
+	Register(ctx context.Context, email, password string, profile dto.ProfileDTO) (dto.UserDTO, error)
+	Login(ctx context.Context, email, password string) (dto.TokenDTO, error)
+	GetUser(ctx context.Context, id string) (dto.UserDTO, error)
+	UpdateProfile(ctx context.Context, id string, profile dto.ProfileDTO) (dto.UserDTO, error)
+}
+
+type userService struct {
+	logger *zap.Logger
+	issuer *auth.Issuer
+	repo   repositories.UserRepository
+}
+
+func NewUserService(logger *zap.Logger, issuer *auth.Issuer, repo repositories.UserRepository) UserService {
+	return &userService{
+		logger: logger,
+		issuer: issuer,
+		repo:   repo,
+	}
+}
+
+func (s *userService) Register(ctx context.Context, email, password string, profile dto.ProfileDTO) (dto.UserDTO, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return dto.UserDTO{}, errors.Wrap(err, "failed to hash password")
+	}
+
+	user := models.User{
+		Email:        normalizeEmail(email),
+		PasswordHash: string(hash),
+	}
+	mapper.ApplyProfileToUserModel(profile, &user)
+
+	created, err := s.repo.Create(ctx, &user)
+	if err != nil {
+		return dto.UserDTO{}, errors.Wrap(err, "failed to create user")
+	}
+	if !created {
+		return dto.UserDTO{}, ErrEmailTaken
+	}
+
+	s.logger.Info("user registered", zap.String("id", user.ID.String()))
+	return mapper.MapUserModelToDTO(user), nil
+}
+
+// Login checks the password of the user and returns a token the user is authenticated with.
+func (s *userService) Login(ctx context.Context, email, password string) (dto.TokenDTO, error) {
+	user, err := s.repo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.TokenDTO{}, errors.Wrap(err, "failed to get user")
+		}
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return dto.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return dto.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	token, expiresAt, err := s.issuer.Issue(user.ID.String(), []string{auth.ScopeUser})
+	if err != nil {
+		return dto.TokenDTO{}, err
+	}
+	return dto.TokenDTO{AccessToken: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, id string) (dto.UserDTO, error) {
+	user, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return dto.UserDTO{}, errors.Wrap(err, "failed to get user")
+	}
+	return mapper.MapUserModelToDTO(user), nil
+}
+
+// UpdateProfile replaces the profile of the user.
+func (s *userService) UpdateProfile(ctx context.Context, id string, profile dto.ProfileDTO) (dto.UserDTO, error) {
+	user, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return dto.UserDTO{}, errors.Wrap(err, "failed to get user")
+	}
+
+	mapper.ApplyProfileToUserModel(profile, &user)
+	if err := s.repo.UpdateProfile(ctx, &user); err != nil {
+		return dto.UserDTO{}, errors.Wrap(err, "failed to update profile")
+	}
+	return mapper.MapUserModelToDTO(user), nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
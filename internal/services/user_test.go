@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"financing-aggregator/internal/auth"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	mock_repositories "financing-aggregator/internal/mocks/repositories"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type userServiceTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	userRepository *mock_repositories.MockUserRepository
+
+	authConfig config.AuthConfig
+	service    UserService
+}
+
+func TestUserSuite(t *testing.T) {
+	suite.Run(t, new(userServiceTestSuite))
+}
+
+func (s *userServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userRepository = mock_repositories.NewMockUserRepository(s.ctrl)
+
+	s.authConfig = config.AuthConfig{Issuer: "financing-aggregator", HS256Secret: "a-test-secret-of-at-least-32-bytes"}
+	s.service = NewUserService(zap.NewNop(), auth.NewIssuer(s.authConfig), s.userRepository)
+}
+
+func (s *userServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *userServiceTestSuite) Test_Register() {
+	s.Run("user created with hashed password and profile", func() {
+		s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, user *models.User) (bool, error) {
+				s.Equal("anakin@skywalker.com", user.Email)
+				s.NoError(bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("password123")))
+				s.Equal(getTestProfile().Phone, user.Phone)
+				s.Equal(getTestProfile().MonthlyIncome, user.MonthlyIncome)
+				user.ID = uuid.New()
+				return true, nil
+			})
+
+		actual, err := s.service.Register(context.Background(), " Anakin@Skywalker.com", "password123", getTestProfile())
+		s.NoError(err)
+		s.NotEmpty(actual.ID)
+		s.Equal("anakin@skywalker.com", actual.Email)
+		s.Equal(getTestProfile(), actual.Profile)
+	})
+
+	s.Run("error occurs because email is taken", func() {
+		s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(false, nil)
+
+		_, err := s.service.Register(context.Background(), "anakin@skywalker.com", "password123", getTestProfile())
+		s.ErrorIs(err, ErrEmailTaken)
+	})
+}
+
+func (s *userServiceTestSuite) Test_Login() {
+	user := getTestUser("password123")
+
+	s.Run("token issued for valid credentials", func() {
+		s.userRepository.EXPECT().GetByEmail(gomock.Any(), "anakin@skywalker.com").Return(user, nil)
+
+		actual, err := s.service.Login(context.Background(), "Anakin@Skywalker.com", "password123")
+		s.NoError(err)
+
+		verifier, err := auth.New(s.authConfig)
+		s.Require().NoError(err)
+		principal, err := verifier.Verify(context.Background(), actual.AccessToken)
+		s.NoError(err)
+
+		userID, ok := principal.UserID()
+		s.True(ok)
+		s.Equal(user.ID.String(), userID)
+	})
+
+	s.Run("error occurs because password is wrong", func() {
+		s.userRepository.EXPECT().GetByEmail(gomock.Any(), "anakin@skywalker.com").Return(user, nil)
+
+		_, err := s.service.Login(context.Background(), "anakin@skywalker.com", "password456")
+		s.ErrorIs(err, ErrInvalidCredentials)
+	})
+
+	s.Run("error occurs because user does not exist", func() {
+		s.userRepository.EXPECT().GetByEmail(gomock.Any(), "vader@empire.com").Return(models.User{}, gorm.ErrRecordNotFound)
+
+		_, err := s.service.Login(context.Background(), "vader@empire.com", "password123")
+		s.ErrorIs(err, ErrInvalidCredentials)
+	})
+
+	s.Run("error occurs because issuing is disabled", func() {
+		service := NewUserService(zap.NewNop(), auth.NewIssuer(config.AuthConfig{}), s.userRepository)
+		s.userRepository.EXPECT().GetByEmail(gomock.Any(), "anakin@skywalker.com").Return(user, nil)
+
+		_, err := service.Login(context.Background(), "anakin@skywalker.com", "password123")
+		s.ErrorIs(err, auth.ErrIssuingDisabled)
+	})
+}
+
+func (s *userServiceTestSuite) Test_UpdateProfile() {
+	user := getTestUser("password123")
+	profile := getTestProfile()
+	profile.Dependents = 0
+	profile.MaritalStatus = models.MaritalStatusMarried
+
+	s.userRepository.EXPECT().Get(gomock.Any(), user.ID.String()).Return(user, nil)
+	s.userRepository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, actual *models.User) error {
+			s.Equal(user.ID, actual.ID)
+			s.Equal(0, actual.Dependents)
+			s.Equal(models.MaritalStatusMarried, actual.MaritalStatus)
+			return nil
+		})
+
+	actual, err := s.service.UpdateProfile(context.Background(), user.ID.String(), profile)
+	s.NoError(err)
+	s.Equal(profile, actual.Profile)
+}
+
+func getTestUser(password string) models.User {
+	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	profile := getTestProfile()
+	return models.User{
+		ID:                       uuid.New(),
+		Email:                    "anakin@skywalker.com",
+		PasswordHash:             string(hash),
+		Phone:                    profile.Phone,
+		MonthlyIncome:            profile.MonthlyIncome,
+		MonthlyExpenses:          profile.MonthlyExpenses,
+		MonthlyCreditLiabilities: profile.MonthlyCreditLiabilities,
+		MaritalStatus:            profile.MaritalStatus,
+		Dependents:               profile.Dependents,
+	}
+}
+
+func getTestProfile() dto.ProfileDTO {
+	return dto.ProfileDTO{
+		Phone:                    "+37122334455",
+		MonthlyIncome:            money.MustParse("2500"),
+		MonthlyExpenses:          money.MustParse("800"),
+		MonthlyCreditLiabilities: money.MustParse("150"),
+		MaritalStatus:            models.MaritalStatusSingle,
+		Dependents:               2,
+	}
+}
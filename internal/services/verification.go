@@ -0,0 +1,33 @@
+package services
+
+import (
+	"financing-aggregator/internal/mapper"
+	"financing-aggregator/internal/models"
+	"financing-aggregator/internal/money"
+	"strings"
+)
+
+// verifyOffer stores offer figures recomputed from the payment schedule of the borrowed
+// principal on the offer. Offers whose stated figures diverge from the computed ones are
+// flagged and, if configured, quarantined. Events describing the divergence are returned.
+func (s *applicationService) verifyOffer(offer *models.Offer, principal money.Amount) []models.OfferEvent {
+	if offer.NumberOfPayments == 0 {
+		return nil
+	}
+
+	result := s.verifier.Verify(principal, mapper.MapOfferModelToDTO(*offer))
+	offer.ComputedAnnualPercentageRate = result.AnnualPercentageRate
+	offer.ComputedTotalRepaymentAmount = result.TotalRepaymentAmount
+	offer.Verification = models.OfferVerificationVerified
+	if len(result.Mismatches) == 0 {
+		return nil
+	}
+
+	offer.Verification = models.OfferVerificationMismatch
+	offer.Quarantined = s.cfg.OfferVerification.Quarantine
+	details := strings.Join(result.Mismatches, ", ")
+	if offer.Quarantined {
+		details += ", offer quarantined"
+	}
+	return []models.OfferEvent{newOfferEvent(*offer, models.OfferEventVerificationFailed, offer.Status, details)}
+}
@@ -0,0 +1,59 @@
+package verification
+
+import (
+	"financing-aggregator/internal/amortization"
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"fmt"
+	"math"
+)
+
+// Result holds offer figures recomputed from the payment schedule.
+type Result struct {
+	AnnualPercentageRate float64
+	TotalRepaymentAmount money.Amount
+	// Mismatches describes stated figures that diverge from computed ones beyond tolerance.
+	Mismatches []string
+}
+
+// Verifier checks that offer figures stated by banks are consistent with their payment
+// schedule of NumberOfPayments equal monthly payments.
+type Verifier struct {
+	cfg config.OfferVerificationConfig
+}
+
+func New(cfg config.OfferVerificationConfig) *Verifier {
+	return &Verifier{cfg: cfg}
+}
+
+// Verify recomputes the total repayment and effective APR of the offer for the borrowed
+// principal and compares them with the figures stated by the bank.
+func (v *Verifier) Verify(principal money.Amount, offer dto.OfferDTO) Result {
+	result := Result{
+		AnnualPercentageRate: round(EffectiveAPR(principal, offer.MonthlyPaymentAmount, offer.NumberOfPayments), 3),
+		TotalRepaymentAmount: offer.MonthlyPaymentAmount.Mul(float64(offer.NumberOfPayments)),
+	}
+
+	if math.Abs(result.AnnualPercentageRate-offer.AnnualPercentageRate) > v.cfg.APRTolerance {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("annualPercentageRate: stated %v, computed %v",
+			offer.AnnualPercentageRate, result.AnnualPercentageRate))
+	}
+	if (result.TotalRepaymentAmount - offer.TotalRepaymentAmount).Abs().Float64() > v.cfg.TotalRepaymentTolerance {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("totalRepaymentAmount: stated %v, computed %v",
+			offer.TotalRepaymentAmount, result.TotalRepaymentAmount))
+	}
+	return result
+}
+
+// EffectiveAPR returns the effective annual rate in percent at which numberOfPayments
+// monthly payments repay the principal.
+func EffectiveAPR(principal, payment money.Amount, numberOfPayments int) float64 {
+	rate := amortization.MonthlyRate(principal.Float64(), payment.Float64(), numberOfPayments)
+	return (math.Pow(1+rate, 12) - 1) * 100
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,62 @@
+package verification
+
+import (
+	"financing-aggregator/internal/config"
+	"financing-aggregator/internal/dto"
+	"financing-aggregator/internal/money"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type verificationTestSuite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(verificationTestSuite))
+}
+
+func (s *verificationTestSuite) Test_EffectiveAPR() {
+	s.Run("no interest", func() {
+		s.InDelta(0, EffectiveAPR(money.MustParse("1200"), money.MustParse("100"), 12), 0.001)
+	})
+
+	s.Run("monthly rate compounded over a year", func() {
+		// 1% per month repays 1000 with 12 payments of 88.85.
+		s.InDelta(12.685, EffectiveAPR(money.MustParse("1000"), money.MustParse("88.85"), 12), 0.001)
+	})
+
+	s.Run("terms missing", func() {
+		s.Zero(EffectiveAPR(money.MustParse("1000"), 0, 0))
+	})
+}
+
+func (s *verificationTestSuite) Test_Verify() {
+	verifier := New(config.OfferVerificationConfig{APRTolerance: 0.5, TotalRepaymentTolerance: 1})
+
+	s.Run("stated figures consistent", func() {
+		actual := verifier.Verify(money.MustParse("1000"), getTestOffer(12.68, 1066.2))
+		s.Equal(12.685, actual.AnnualPercentageRate)
+		s.Equal(money.MustParse("1066.20"), actual.TotalRepaymentAmount)
+		s.Empty(actual.Mismatches)
+	})
+
+	s.Run("stated apr diverges", func() {
+		actual := verifier.Verify(money.MustParse("1000"), getTestOffer(10, 1066.2))
+		s.Equal([]string{"annualPercentageRate: stated 10, computed 12.685"}, actual.Mismatches)
+	})
+
+	s.Run("stated total repayment diverges", func() {
+		actual := verifier.Verify(money.MustParse("1000"), getTestOffer(12.68, 1000))
+		s.Equal([]string{"totalRepaymentAmount: stated 1000.00, computed 1066.20"}, actual.Mismatches)
+	})
+}
+
+func getTestOffer(apr, total float64) dto.OfferDTO {
+	return dto.OfferDTO{
+		MonthlyPaymentAmount: money.MustParse("88.85"),
+		TotalRepaymentAmount: money.FromFloat(total),
+		NumberOfPayments:     12,
+		AnnualPercentageRate: apr,
+	}
+}
@@ -3,7 +3,16 @@
 INTERNAL_MOCKS_DIR=internal/mocks
 INTERNAL_FILES=(
   repositories/application.go
+  repositories/idempotency_key.go
+  repositories/merchant.go
   repositories/offer.go
+  repositories/offer_event.go
+  repositories/offer_update.go
+  repositories/submission.go
+  repositories/user.go
+  services/idempotency.go
+  services/merchant.go
+  services/user.go
   banks/bank.go
   controllers/ws/ws.go
 )